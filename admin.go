@@ -0,0 +1,66 @@
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// requireAdmin rejects requests that don't carry the admin bearer token.
+// Admin endpoints are disabled entirely when no token is configured.
+func requireAdmin(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			writeJSON(w, http.StatusForbidden, map[string]interface{}{
+				"success": false,
+				"error":   "Admin endpoints are disabled",
+			})
+			return
+		}
+
+		given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goverter-admin"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"success": false,
+				"error":   "Invalid or missing admin token",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Handler for the registry reload endpoint
+func reloadHandler(uc *converter.UnitConverter, audit *AuditLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		diff, err := uc.Reload()
+		if err != nil {
+			log.Printf("Error reloading unit definitions: %v", err)
+			audit.RecordRequest(r, "admin", AuditRegistryReload, map[string]interface{}{"error": err.Error()})
+			result := map[string]interface{}{
+				"success": false,
+				"error":   "Reload failed: " + err.Error(),
+			}
+			var invalid *converter.RegistryValidationError
+			if errors.As(err, &invalid) {
+				result["problems"] = invalid.Problems
+			}
+			writeJSON(w, http.StatusInternalServerError, result)
+			return
+		}
+
+		log.Printf("Unit definitions reloaded: %d added, %d changed, %d removed",
+			len(diff.Added), len(diff.Changed), len(diff.Removed))
+		audit.RecordRequest(r, "admin", AuditRegistryReload, map[string]interface{}{"diff": diff})
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"diff":    diff,
+		})
+	}
+}
@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiKeyHeader carries the API key of a request.
+const apiKeyHeader = "X-API-Key"
+
+// APIKey is a named key with its request limits. A limit of zero means
+// unlimited.
+type APIKey struct {
+	Name      string
+	Secret    string
+	Daily     int64 // Requests per UTC day
+	Monthly   int64 // Requests per UTC calendar month
+	PerMinute int64 // Rate limit
+}
+
+type apiKeyNameKey struct{}
+
+// APIKeyName returns the name of the API key that authenticated the request
+// carrying ctx, or "".
+func APIKeyName(ctx context.Context) string {
+	name, _ := ctx.Value(apiKeyNameKey{}).(string)
+	return name
+}
+
+// ParseAPIKey reads a "name:secret" key definition, optionally followed by
+// ":daily:monthly:per_minute" limits. Empty limits take the given defaults.
+func ParseAPIKey(definition string, defaults APIKey) (APIKey, error) {
+	fields := strings.Split(definition, ":")
+	if len(fields) != 2 && len(fields) != 5 {
+		return APIKey{}, fmt.Errorf("invalid API key %q, expected name:secret or name:secret:daily:monthly:per_minute", redactKey(definition))
+	}
+	key := defaults
+	key.Name, key.Secret = strings.TrimSpace(fields[0]), strings.TrimSpace(fields[1])
+	if key.Name == "" || key.Secret == "" {
+		return APIKey{}, fmt.Errorf("invalid API key %q, name and secret are required", redactKey(definition))
+	}
+	if len(fields) == 5 {
+		for i, limit := range []*int64{&key.Daily, &key.Monthly, &key.PerMinute} {
+			raw := strings.TrimSpace(fields[2+i])
+			if raw == "" {
+				continue
+			}
+			n, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil || n < 0 {
+				return APIKey{}, fmt.Errorf("API key %s: invalid limit %q", key.Name, raw)
+			}
+			*limit = n
+		}
+	}
+	return key, nil
+}
+
+// redactKey hides the secret of a key definition for error messages.
+func redactKey(definition string) string {
+	name, _, _ := strings.Cut(definition, ":")
+	return name + ":…"
+}
+
+// usageWindow counts requests in one fixed period.
+type usageWindow struct {
+	start time.Time
+	count int64
+}
+
+// keyUsage is the consumption of one key.
+type keyUsage struct {
+	minute, day, month usageWindow
+	total              int64
+}
+
+// windowBounds returns the start and end of the minute, UTC day and UTC
+// month containing now.
+func windowBounds(period string, now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	switch period {
+	case "minute":
+		start = now.Truncate(time.Minute)
+		return start, start.Add(time.Minute)
+	case "day":
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 0, 1)
+	default:
+		start = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// QuotaError reports a request refused because a key used up a limit.
+type QuotaError struct {
+	Key    string
+	Period string // "minute", "day" or "month"
+	Limit  int64
+	Reset  time.Time // When the limit's window starts over
+}
+
+func (e *QuotaError) Error() string {
+	switch e.Period {
+	case "minute":
+		return fmt.Sprintf("Rate limit of %d requests per minute exceeded", e.Limit)
+	case "day":
+		return fmt.Sprintf("Daily quota of %d requests exceeded", e.Limit)
+	default:
+		return fmt.Sprintf("Monthly quota of %d requests exceeded", e.Limit)
+	}
+}
+
+// UsageWindow is the consumption of a key in one period.
+type UsageWindow struct {
+	Used      int64     `json:"used"`
+	Limit     int64     `json:"limit,omitempty"`     // Absent when unlimited
+	Remaining *int64    `json:"remaining,omitempty"` // Absent when unlimited
+	Reset     time.Time `json:"reset"`
+}
+
+// Usage is the response of the usage endpoint.
+type Usage struct {
+	Success bool                   `json:"success"`
+	Error   string                 `json:"error,omitempty"`
+	Key     string                 `json:"key,omitempty"`
+	Total   int64                  `json:"total"` // Requests since the server started
+	Windows map[string]UsageWindow `json:"usage,omitempty"`
+}
+
+// APIKeyStore authenticates API keys and meters their requests. Counters
+// live in memory and start over when the server restarts.
+type APIKeyStore struct {
+	keys    []APIKey
+	require bool // Refuse metered requests without a key
+
+	mu    sync.Mutex
+	usage map[string]*keyUsage // By key name
+}
+
+// NewAPIKeyStore creates a store for keys. With require set, metered
+// endpoints can't be used anonymously.
+func NewAPIKeyStore(keys []APIKey, require bool) *APIKeyStore {
+	return &APIKeyStore{keys: keys, require: require, usage: make(map[string]*keyUsage)}
+}
+
+// Enabled reports whether any keys are configured.
+func (s *APIKeyStore) Enabled() bool {
+	return len(s.keys) > 0
+}
+
+// Authenticate returns the key with the given secret.
+func (s *APIKeyStore) Authenticate(secret string) (APIKey, bool) {
+	var found APIKey
+	ok := false
+	// Compare against every key so timing doesn't reveal which one matched
+	for _, key := range s.keys {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(key.Secret)) == 1 {
+			found, ok = key, true
+		}
+	}
+	return found, ok
+}
+
+// roll starts new windows for usage once their period is over.
+func (u *keyUsage) roll(now time.Time) {
+	for period, window := range map[string]*usageWindow{"minute": &u.minute, "day": &u.day, "month": &u.month} {
+		if start, _ := windowBounds(period, now); !window.start.Equal(start) {
+			*window = usageWindow{start: start}
+		}
+	}
+}
+
+// Record counts a request by key, or returns a *QuotaError without counting
+// it when a limit is used up.
+func (s *APIKeyStore) Record(key APIKey, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usage[key.Name]
+	if u == nil {
+		u = &keyUsage{}
+		s.usage[key.Name] = u
+	}
+	u.roll(now)
+
+	for _, l := range []struct {
+		period string
+		limit  int64
+		window *usageWindow
+	}{
+		{"minute", key.PerMinute, &u.minute},
+		{"day", key.Daily, &u.day},
+		{"month", key.Monthly, &u.month},
+	} {
+		if l.limit > 0 && l.window.count >= l.limit {
+			_, reset := windowBounds(l.period, now)
+			return &QuotaError{Key: key.Name, Period: l.period, Limit: l.limit, Reset: reset}
+		}
+	}
+	u.minute.count++
+	u.day.count++
+	u.month.count++
+	u.total++
+	return nil
+}
+
+// Usage reports the consumption of key.
+func (s *APIKeyStore) Usage(key APIKey, now time.Time) Usage {
+	s.mu.Lock()
+	u := keyUsage{}
+	if stored := s.usage[key.Name]; stored != nil {
+		stored.roll(now)
+		u = *stored
+	}
+	s.mu.Unlock()
+
+	window := func(period string, used, limit int64) UsageWindow {
+		_, reset := windowBounds(period, now)
+		w := UsageWindow{Used: used, Limit: limit, Reset: reset}
+		if limit > 0 {
+			remaining := max(limit-used, 0)
+			w.Remaining = &remaining
+		}
+		return w
+	}
+	return Usage{
+		Success: true,
+		Key:     key.Name,
+		Total:   u.total,
+		Windows: map[string]UsageWindow{
+			"minute": window("minute", u.minute.count, key.PerMinute),
+			"day":    window("day", u.day.count, key.Daily),
+			"month":  window("month", u.month.count, key.Monthly),
+		},
+	}
+}
+
+// metered reports whether requests to path count against API key limits.
+// Namespaced endpoints count like their global counterparts.
+func metered(path string) bool {
+	if _, rest, ok := splitNamespacePath(path); ok {
+		path = rest
+	}
+	return path == "/convert" || (strings.HasPrefix(path, "/api/") && path != "/api/usage")
+}
+
+// withAPIKeyName returns r carrying the name of the key that authenticated it.
+func withAPIKeyName(r *http.Request, name string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), apiKeyNameKey{}, name))
+}
+
+// Middleware authenticates and meters requests to the API. Requests without
+// a key pass through unless keys are required; requests with an unknown key
+// are refused. OPTIONS requests only describe an endpoint and are never
+// metered. Unmetered paths still record a valid key, so its namespace
+// applies to them, but never refuse a request.
+func (s *APIKeyStore) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.Enabled() || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !metered(r.URL.Path) {
+			if secret := r.Header.Get(apiKeyHeader); secret != "" {
+				if key, ok := s.Authenticate(secret); ok {
+					r = withAPIKeyName(r, key.Name)
+				}
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secret := r.Header.Get(apiKeyHeader)
+		if secret == "" && !s.require {
+			next.ServeHTTP(w, r)
+			return
+		}
+		key, ok := s.Authenticate(secret)
+		if !ok {
+			writeJSON(w, http.StatusUnauthorized, map[string]interface{}{
+				"success": false,
+				"error":   "Invalid or missing API key",
+			})
+			return
+		}
+
+		now := time.Now()
+		if err := s.Record(key, now); err != nil {
+			quota := err.(*QuotaError)
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(quota.Reset.Sub(now).Seconds())+1, 10))
+			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(quota.Limit, 10))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(quota.Reset.Unix(), 10))
+			writeJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+				"success": false,
+				"error":   quota.Error(),
+				"period":  quota.Period,
+				"limit":   quota.Limit,
+				"reset":   quota.Reset,
+			})
+			return
+		}
+		if key.PerMinute > 0 {
+			usage := s.Usage(key, now).Windows["minute"]
+			w.Header().Set("X-RateLimit-Limit", strconv.FormatInt(key.PerMinute, 10))
+			w.Header().Set("X-RateLimit-Remaining", strconv.FormatInt(*usage.Remaining, 10))
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(usage.Reset.Unix(), 10))
+		}
+		next.ServeHTTP(w, withAPIKeyName(r, key.Name))
+	})
+}
+
+// Handler for the API usage endpoint
+func usageHandler(s *APIKeyStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.Enabled() {
+			writeJSON(w, http.StatusNotFound, Usage{Success: false, Error: "API keys are not configured"})
+			return
+		}
+		key, ok := s.Authenticate(r.Header.Get(apiKeyHeader))
+		if !ok {
+			writeJSON(w, http.StatusUnauthorized, Usage{Success: false, Error: "Invalid or missing API key"})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.Usage(key, time.Now()))
+	}
+}
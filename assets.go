@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// staticFiles holds the compiled stylesheet and any other static assets,
+// so a release binary serves exactly the files it was built with.
+//
+//go:embed static
+var staticFiles embed.FS
+
+// assetHashLength is the number of hex digits of the SHA-256 content hash
+// put into fingerprinted file names.
+const assetHashLength = 10
+
+// Cache policies for fingerprinted and plain asset URLs. A hashed name
+// changes with its content, so browsers may keep it forever; plain names
+// must be revalidated on every use.
+const (
+	immutableCacheControl  = "public, max-age=31536000, immutable"
+	revalidateCacheControl = "no-cache"
+)
+
+// asset is one embedded static file together with its fingerprint.
+type asset struct {
+	name    string // path below static/, e.g. "output.css"
+	hashed  string // fingerprinted path, e.g. "output.1a2b3c4d5e.css"
+	etag    string
+	content []byte
+}
+
+// Assets indexes the embedded static files by plain and fingerprinted
+// name. It is computed once at startup and never modified afterwards.
+type Assets struct {
+	byName   map[string]*asset
+	byHashed map[string]*asset
+}
+
+// staticAssets fingerprints the embedded static directory at startup.
+var staticAssets = mustLoadAssets(staticFiles, "static")
+
+// mustLoadAssets is LoadAssets for package initialisation; a broken embed
+// is a build problem, not something to recover from.
+func mustLoadAssets(fsys fs.FS, dir string) *Assets {
+	a, err := LoadAssets(fsys, dir)
+	if err != nil {
+		panic("loading static assets: " + err.Error())
+	}
+	return a
+}
+
+// LoadAssets reads every file below dir and derives its fingerprinted
+// name from the SHA-256 hash of its content.
+func LoadAssets(fsys fs.FS, dir string) (*Assets, error) {
+	a := &Assets{
+		byName:   make(map[string]*asset),
+		byHashed: make(map[string]*asset),
+	}
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(content)
+		hash := hex.EncodeToString(sum[:])[:assetHashLength]
+		name := strings.TrimPrefix(p, dir+"/")
+		entry := &asset{
+			name:    name,
+			hashed:  fingerprint(name, hash),
+			etag:    `"` + hash + `"`,
+			content: content,
+		}
+		a.byName[entry.name] = entry
+		a.byHashed[entry.hashed] = entry
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// fingerprint inserts hash before the extension of name:
+// "css/output.css" becomes "css/output.<hash>.css".
+func fingerprint(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// Path returns the URL of the fingerprinted copy of name, relative to the
+// base path. Templates reach it through the asset function. Unknown names
+// fall back to the plain /static/ URL so a missing file shows up as a 404
+// in the browser rather than breaking the page render.
+func (a *Assets) Path(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if entry, ok := a.byName[name]; ok {
+		return "/static/" + entry.hashed
+	}
+	return "/static/" + name
+}
+
+// Handler for the /static/ endpoint. Fingerprinted names are served with
+// immutable cache headers; plain names still work for old pages and
+// external links but must be revalidated, which the ETag makes cheap.
+func (a *Assets) Handler() http.Handler {
+	return http.StripPrefix("/static/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if entry, ok := a.byHashed[r.URL.Path]; ok {
+			w.Header().Set("Cache-Control", immutableCacheControl)
+			a.serve(w, r, entry)
+			return
+		}
+		if entry, ok := a.byName[r.URL.Path]; ok {
+			w.Header().Set("Cache-Control", revalidateCacheControl)
+			a.serve(w, r, entry)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+}
+
+// serve writes one asset, answering conditional requests against its
+// ETag. Embedded files carry no modification time, so none is sent.
+func (a *Assets) serve(w http.ResponseWriter, r *http.Request, entry *asset) {
+	w.Header().Set("ETag", entry.etag)
+	http.ServeContent(w, r, entry.name, time.Time{}, bytes.NewReader(entry.content))
+}
@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditRegistryReload = "registry.reload"
+	AuditCatalogImport  = "catalog.import"
+	AuditWebhookCreate  = "webhook.create" // Issues a webhook signing secret
+	AuditWebhookDelete  = "webhook.delete"
+	AuditAPIKeysLoad    = "api_keys.load" // The API keys in effect at startup
+	AuditConversion     = "conversion"    // Only recorded when enabled
+)
+
+// maxAuditEntries bounds an audit log kept only in memory; the oldest
+// entries are dropped beyond it.
+const maxAuditEntries = 100000
+
+// AuditEntry is one audited action.
+type AuditEntry struct {
+	ID      int64                  `json:"id"`
+	Time    time.Time              `json:"time"`
+	Action  string                 `json:"action"`
+	Actor   string                 `json:"actor"` // "admin", "key:<name>", "anonymous" or "system"
+	Remote  string                 `json:"remote,omitempty"`
+	TraceID string                 `json:"traceId,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// AuditLog is an append-only log of administrative actions and, optionally,
+// conversions. With a file, entries are appended to it as JSON lines and
+// survive restarts; otherwise they are kept in memory. Entries are never
+// changed, only dropped by Prune once they are older than the retention.
+type AuditLog struct {
+	path        string
+	retention   time.Duration // Zero keeps entries forever
+	conversions bool
+
+	mu      sync.Mutex
+	file    *os.File
+	entries []AuditEntry // Only used without a file
+	nextID  int64
+}
+
+// OpenAuditLog opens the audit log at path, creating it if needed. An empty
+// path keeps the log in memory.
+func OpenAuditLog(path string, retention time.Duration, conversions bool) (*AuditLog, error) {
+	a := &AuditLog{path: path, retention: retention, conversions: conversions, nextID: 1}
+	if path == "" {
+		return a, nil
+	}
+	entries, err := readAuditFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		a.nextID = entries[len(entries)-1].ID + 1
+	}
+	if a.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// readAuditFile reads the entries of an audit log file, oldest first.
+func readAuditFile(path string) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", path, lineNo, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Record appends an entry, assigning its ID and, when unset, its time.
+// Conversions are skipped unless the log was opened to record them.
+func (a *AuditLog) Record(entry AuditEntry) {
+	if entry.Action == AuditConversion && !a.conversions {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entry.ID = a.nextID
+	a.nextID++
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Time = entry.Time.UTC()
+
+	if a.file == nil {
+		a.entries = append(a.entries, entry)
+		if len(a.entries) > maxAuditEntries {
+			a.entries = append(a.entries[:0], a.entries[len(a.entries)-maxAuditEntries:]...)
+		}
+		return
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("Error encoding audit entry %s: %v", entry.Action, err)
+		return
+	}
+	if _, err := a.file.Write(append(line, '\n')); err != nil {
+		log.Printf("Error writing audit entry %s: %v", entry.Action, err)
+	}
+}
+
+// RecordRequest appends an entry for an action taken through r by actor.
+func (a *AuditLog) RecordRequest(r *http.Request, actor, action string, details map[string]interface{}) {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	a.Record(AuditEntry{
+		Action:  action,
+		Actor:   actor,
+		Remote:  remote,
+		TraceID: TraceID(r.Context()),
+		Details: details,
+	})
+}
+
+// requestActor names who made an API request: its API key, if any.
+func requestActor(r *http.Request) string {
+	if name := APIKeyName(r.Context()); name != "" {
+		return "key:" + name
+	}
+	return "anonymous"
+}
+
+// Entries returns the entries within [from, to), oldest first, optionally
+// only those of one action. Zero times leave that side of the range open.
+func (a *AuditLog) Entries(from, to time.Time, action string) ([]AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	all := a.entries
+	if a.file != nil {
+		var err error
+		if all, err = readAuditFile(a.path); err != nil {
+			return nil, err
+		}
+	}
+	entries := []AuditEntry{}
+	for _, entry := range all {
+		if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && !entry.Time.Before(to)) {
+			continue
+		}
+		if action != "" && entry.Action != action {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Prune drops entries older than the retention. A log file is rewritten
+// without them and then replaces the old one.
+func (a *AuditLog) Prune(now time.Time) error {
+	if a.retention <= 0 {
+		return nil
+	}
+	cutoff := now.Add(-a.retention)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		kept := a.entries[:0]
+		for _, entry := range a.entries {
+			if !entry.Time.Before(cutoff) {
+				kept = append(kept, entry)
+			}
+		}
+		a.entries = kept
+		return nil
+	}
+
+	entries, err := readAuditFile(a.path)
+	if err != nil {
+		return err
+	}
+	expired := 0
+	for expired < len(entries) && entries[expired].Time.Before(cutoff) {
+		expired++
+	}
+	if expired == 0 {
+		return nil
+	}
+
+	tmp := a.path + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	for _, entry := range entries[expired:] {
+		if err := enc.Encode(entry); err != nil {
+			out.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, a.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	a.file.Close()
+	if a.file, err = os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err != nil {
+		return err
+	}
+	log.Printf("Audit log pruned: %d entries older than %s removed", expired, cutoff.UTC().Format(time.RFC3339))
+	return nil
+}
+
+// Handler for the audit log export endpoint
+func auditExportHandler(audit *AuditLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+		}
+
+		query := r.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = "json"
+		}
+		if format != "json" && format != "csv" {
+			fail(http.StatusBadRequest, fmt.Sprintf("unknown format %q (use csv or json)", format))
+			return
+		}
+		from, err := parseHistoryTime(query.Get("from"), false)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		to, err := parseHistoryTime(query.Get("to"), true)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		entries, err := audit.Entries(from, to, query.Get("action"))
+		if err != nil {
+			log.Printf("Error reading audit log: %v", err)
+			fail(http.StatusInternalServerError, "Error reading audit log")
+			return
+		}
+		writeAudit(w, format, entries)
+	}
+}
+
+// writeAudit sends audit entries as a file download.
+func writeAudit(w http.ResponseWriter, format string, entries []AuditEntry) {
+	filename := "goverter-audit-" + time.Now().UTC().Format("20060102") + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	out := csv.NewWriter(w)
+	out.Write([]string{"id", "time", "action", "actor", "remote", "trace_id", "details"})
+	for _, entry := range entries {
+		details := ""
+		if len(entry.Details) > 0 {
+			encoded, _ := json.Marshal(entry.Details)
+			details = string(encoded)
+		}
+		out.Write([]string{
+			strconv.FormatInt(entry.ID, 10),
+			entry.Time.UTC().Format(time.RFC3339),
+			entry.Action,
+			entry.Actor,
+			entry.Remote,
+			entry.TraceID,
+			details,
+		})
+	}
+	out.Flush()
+}
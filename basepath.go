@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// basePathKey is the context key under which the URL prefix the app is
+// mounted at is stored.
+type basePathKey struct{}
+
+// NormalizeBasePath turns a configured base path into the form used for
+// matching and building URLs: a leading slash and no trailing slash, e.g.
+// "tools/convert/" becomes "/tools/convert". The root ("" or "/") becomes "".
+func NormalizeBasePath(base string) (string, error) {
+	trimmed := strings.Trim(strings.TrimSpace(base), "/")
+	if trimmed == "" {
+		return "", nil
+	}
+	for _, segment := range strings.Split(trimmed, "/") {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, "?#%\\ \t") {
+			return "", fmt.Errorf("invalid base path %q", base)
+		}
+	}
+	return "/" + trimmed, nil
+}
+
+// BasePath returns the URL prefix the app is mounted at, without a trailing
+// slash, or "" when it is served from the root.
+func BasePath(ctx context.Context) string {
+	base, _ := ctx.Value(basePathKey{}).(string)
+	return base
+}
+
+// basePathMiddleware mounts next under base. The prefix is stripped from
+// requests that carry it; requests without it are served unchanged, so the
+// app works both behind proxies that forward the full path and behind ones
+// that strip the prefix themselves. The bare prefix redirects to its
+// slash-terminated form, like http.ServeMux does for subtrees.
+func basePathMiddleware(base string, next http.Handler) http.Handler {
+	if base == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == base {
+			target := base + "/"
+			if r.URL.RawQuery != "" {
+				target += "?" + r.URL.RawQuery
+			}
+			http.Redirect(w, r, target, http.StatusMovedPermanently)
+			return
+		}
+		ctx := context.WithValue(r.Context(), basePathKey{}, base)
+		if rest, ok := strings.CutPrefix(r.URL.Path, base+"/"); ok {
+			r2 := r.Clone(ctx)
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = "/" + rest
+			r2.URL.RawPath = ""
+			next.ServeHTTP(w, r2)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// pageFuncs returns the template functions building links for r: url
+// prefixes an app path with the base path, and asset gives the
+// fingerprinted URL of a static file.
+func pageFuncs(r *http.Request) template.FuncMap {
+	base := BasePath(r.Context())
+	return template.FuncMap{
+		"url":   func(path string) string { return base + path },
+		"asset": func(name string) string { return base + staticAssets.Path(name) },
+	}
+}
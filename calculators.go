@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// Calculators answer questions that combine a conversion with some
+// arithmetic, such as what a given energy use costs.
+
+// calcError reports a calculator failure as JSON.
+func calcError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+}
+
+// parseQuantity reads a number from the request, naming the field in
+// errors. Missing values are an error unless optional is set.
+func parseQuantity(r *http.Request, field string, optional bool) (float64, bool, error) {
+	raw := strings.TrimSpace(r.FormValue(field))
+	if raw == "" {
+		if optional {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("%s is required", field)
+	}
+	value, err := converter.ParseNumber(raw, requestDecimalComma(r))
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %s is not a number", field, raw)
+	}
+	return value, true, nil
+}
+
+// EnergyCost is the result of the electricity cost calculator.
+type EnergyCost struct {
+	Success     bool    `json:"success"`
+	Energy      float64 `json:"energy"`
+	Unit        string  `json:"unit"`
+	KWh         float64 `json:"kWh"`
+	PricePerKWh float64 `json:"pricePerKWh"`
+	Cost        float64 `json:"cost"`
+	Currency    string  `json:"currency,omitempty"`
+	Formatted   string  `json:"formatted"`
+}
+
+// Handler for the electricity cost calculator
+func energyCostHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			calcError(w, status, message)
+			return
+		}
+
+		energy, _, err := parseQuantity(r, "energy", false)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		price, _, err := parseQuantity(r, "price", false)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if price < 0 {
+			calcError(w, http.StatusBadRequest, "price must not be negative")
+			return
+		}
+		unit := r.FormValue("unit")
+		if unit == "" {
+			unit = "kWh"
+		}
+		currency := strings.ToUpper(strings.TrimSpace(r.FormValue("currency")))
+
+		kWh, cost, err := uc.EnergyCost(energy, unit, price)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		formatted := strconv.FormatFloat(cost, 'f', 2, 64)
+		if currency != "" {
+			formatted += " " + currency
+		}
+		writeJSON(w, http.StatusOK, EnergyCost{
+			Success:     true,
+			Energy:      energy,
+			Unit:        unit,
+			KWh:         kWh,
+			PricePerKWh: price,
+			Cost:        cost,
+			Currency:    currency,
+			Formatted:   formatted,
+		})
+	}
+}
+
+// EnergyUse is the result of the power × time calculator.
+type EnergyUse struct {
+	Success      bool    `json:"success"`
+	Power        float64 `json:"power"`
+	PowerUnit    string  `json:"powerUnit"`
+	Duration     float64 `json:"duration"`
+	DurationUnit string  `json:"durationUnit"`
+	Energy       float64 `json:"energy"`
+	Unit         string  `json:"unit"`
+	Formatted    string  `json:"formatted"`
+}
+
+// Handler for the power × time → energy calculator
+func energyHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			calcError(w, status, message)
+			return
+		}
+
+		power, _, err := parseQuantity(r, "power", false)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		duration, _, err := parseQuantity(r, "duration", false)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		powerUnit := formValueOr(r, "power_unit", "W")
+		durationUnit := formValueOr(r, "duration_unit", "h")
+		to := formValueOr(r, "to", "kWh")
+		if ref := uc.Resolve(powerUnit); ref.Valid() && ref.Unit().Dimension != "power" {
+			calcError(w, http.StatusBadRequest, "power_unit must be a power unit such as W or HP")
+			return
+		}
+		if ref := uc.Resolve(durationUnit); ref.Valid() && ref.Unit().Dimension != "time" {
+			calcError(w, http.StatusBadRequest, "duration_unit must be a time unit such as h or min")
+			return
+		}
+
+		energy, err := uc.Multiply(power, powerUnit, duration, durationUnit, to)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, EnergyUse{
+			Success:      true,
+			Power:        power,
+			PowerUnit:    powerUnit,
+			Duration:     duration,
+			DurationUnit: durationUnit,
+			Energy:       energy,
+			Unit:         to,
+			Formatted:    uc.FormatResult(energy, to),
+		})
+	}
+}
+
+// formValueOr returns a form value, or fallback when it is empty.
+func formValueOr(r *http.Request, key, fallback string) string {
+	if v := strings.TrimSpace(r.FormValue(key)); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// formatDuration renders seconds as e.g. "12 min 32 s" or "2 d 3 h": the
+// most significant part and, when not zero, the one after it.
+func formatDuration(seconds float64) string {
+	if seconds < 0.0005 {
+		return "< 1 ms"
+	}
+	if seconds < 1 {
+		return strconv.FormatFloat(math.Round(seconds*1000), 'f', 0, 64) + " ms"
+	}
+	total := int64(math.Round(seconds))
+	parts := []struct {
+		size  int64
+		label string
+	}{{86400, "d"}, {3600, "h"}, {60, "min"}, {1, "s"}}
+
+	for i, p := range parts {
+		n := total / p.size
+		if n == 0 {
+			continue
+		}
+		out := strconv.FormatInt(n, 10) + " " + p.label
+		if i+1 < len(parts) {
+			next := parts[i+1]
+			if m := total % p.size / next.size; m > 0 {
+				out += " " + strconv.FormatInt(m, 10) + " " + next.label
+			}
+		}
+		return out
+	}
+	return "0 s"
+}
+
+// TransferEstimate is the result of the transfer time calculator.
+type TransferEstimate struct {
+	Success   bool    `json:"success"`
+	Size      float64 `json:"size"`
+	SizeUnit  string  `json:"sizeUnit"`
+	Rate      float64 `json:"rate"`
+	RateUnit  string  `json:"rateUnit"`
+	Prefixes  string  `json:"prefixes"`
+	Seconds   float64 `json:"seconds"`
+	Formatted string  `json:"formatted"`
+}
+
+// Handler for the transfer time calculator
+func transferTimeHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			calcError(w, status, message)
+			return
+		}
+
+		size, _, err := parseQuantity(r, "size", false)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		rate, _, err := parseQuantity(r, "rate", false)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sizeUnit := formValueOr(r, "size_unit", "GB")
+		rateUnit := formValueOr(r, "rate_unit", "Mbit/s")
+		prefixes := formValueOr(r, "prefixes", "decimal")
+		if prefixes != "decimal" && prefixes != "binary" {
+			calcError(w, http.StatusBadRequest, "prefixes must be decimal or binary")
+			return
+		}
+
+		seconds, err := uc.TransferTime(size, sizeUnit, rate, rateUnit, prefixes == "decimal")
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, TransferEstimate{
+			Success:   true,
+			Size:      size,
+			SizeUnit:  sizeUnit,
+			Rate:      rate,
+			RateUnit:  rateUnit,
+			Prefixes:  prefixes,
+			Seconds:   seconds,
+			Formatted: formatDuration(seconds),
+		})
+	}
+}
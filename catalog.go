@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// maxCatalogSize bounds the size of an imported catalog.
+const maxCatalogSize = 5 << 20
+
+// Handler for the catalog export endpoint
+func catalogExportHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Disposition", `attachment; filename="goverter-catalog.json"`)
+		writeJSON(w, http.StatusOK, uc.ExportCatalog())
+	}
+}
+
+// Handler for the catalog import endpoint
+func catalogImportHandler(uc *converter.UnitConverter, audit *AuditLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Catalogs are JSON only; say so rather than report a JSON syntax error
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); strings.Contains(mediaType, "yaml") {
+			writeJSON(w, http.StatusUnsupportedMediaType, map[string]interface{}{
+				"success": false,
+				"error":   "Catalogs are imported as JSON; YAML is not supported",
+			})
+			return
+		}
+
+		var catalog converter.UnitFile
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCatalogSize))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&catalog); err != nil {
+			status, message := bodyError(err, "Invalid catalog: "+err.Error())
+			writeJSON(w, status, map[string]interface{}{
+				"success": false,
+				"error":   message,
+			})
+			return
+		}
+
+		dryRun := r.URL.Query().Get("dry_run") == "1" || r.URL.Query().Get("dry_run") == "true"
+		diff, err := uc.Import(catalog, dryRun)
+		if err != nil {
+			result := map[string]interface{}{
+				"success": false,
+				"error":   "Invalid catalog: " + err.Error(),
+			}
+			var invalid *converter.RegistryValidationError
+			if errors.As(err, &invalid) {
+				result["problems"] = invalid.Problems
+			}
+			writeJSON(w, http.StatusBadRequest, result)
+			return
+		}
+		if !dryRun {
+			details := map[string]interface{}{"diff": diff}
+			if namespace := uc.Namespace(); namespace != "" {
+				details["namespace"] = namespace
+			}
+			audit.RecordRequest(r, "admin", AuditCatalogImport, details)
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"success": true,
+			"dryRun":  dryRun,
+			"diff":    diff,
+		})
+	}
+}
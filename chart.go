@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// maxChartRows keeps printable charts to a few pages.
+const maxChartRows = 200
+
+// chartTable is a conversion chart ready to render.
+type chartTable struct {
+	Title  string
+	Header [2]string
+	Rows   [][2]string
+}
+
+// buildChart converts a range into chart rows. A zero step gives ten rows.
+func buildChart(ctx context.Context, uc *converter.UnitConverter, min, max, step float64, from, to string) (chartTable, error) {
+	if span := max - min; math.IsNaN(span) || math.IsInf(span, 0) {
+		return chartTable{}, fmt.Errorf("range from %g to %g is too wide", min, max)
+	}
+	if step == 0 {
+		step = (max - min) / 10
+	}
+	if step > 0 && !((max-min)/step < maxChartRows) {
+		return chartTable{}, fmt.Errorf("chart would have more than %d rows, use a larger step", maxChartRows)
+	}
+	lo, _, series, err := uc.ConvertRange(ctx, min, max, step, from, to)
+	if err != nil {
+		return chartTable{}, err
+	}
+	if series == nil {
+		// min == max
+		series = []converter.RangePoint{lo}
+	}
+
+	fromSymbol, toSymbol := uc.Resolve(from).Symbol(), uc.Resolve(to).Symbol()
+	chart := chartTable{
+		Title:  fmt.Sprintf("%s to %s", uc.Resolve(from).Unit().Name, uc.Resolve(to).Unit().Name),
+		Header: [2]string{fromSymbol, toSymbol},
+	}
+	for _, point := range series {
+		result := "0"
+		if point.Result != 0 {
+			result = strings.TrimSpace(strings.TrimSuffix(uc.FormatResult(point.Result, toSymbol), toSymbol))
+		}
+		chart.Rows = append(chart.Rows, [2]string{strconv.FormatFloat(point.Value, 'f', -1, 64), result})
+	}
+	return chart, nil
+}
+
+// Handler for the chart endpoints, /api/chart.pdf and /api/chart.png
+func chartHandler(uc *converter.UnitConverter, format string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+		}
+
+		query := r.URL.Query()
+		from, to := query.Get("from"), query.Get("to")
+		if from == "" || to == "" || query.Get("min") == "" || query.Get("max") == "" {
+			fail(http.StatusBadRequest, "All fields (from, to, min, max) are required")
+			return
+		}
+		var bounds [3]float64
+		for i, name := range []string{"min", "max", "step"} {
+			raw := query.Get(name)
+			if raw == "" {
+				continue
+			}
+			value, err := converter.ParseNumber(raw, requestDecimalComma(r))
+			if err != nil {
+				fail(http.StatusBadRequest, "Invalid "+name+": "+raw+" is not a number")
+				return
+			}
+			bounds[i] = value
+		}
+		if bounds[2] < 0 {
+			fail(http.StatusBadRequest, "Step must be positive")
+			return
+		}
+
+		chart, err := buildChart(r.Context(), uc, bounds[0], bounds[1], bounds[2], from, to)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		var buf bytes.Buffer
+		if format == "pdf" {
+			w.Header().Set("Content-Type", "application/pdf")
+			writeChartPDF(&buf, chart)
+		} else {
+			w.Header().Set("Content-Type", "image/png")
+			png.Encode(&buf, renderChartPNG(chart))
+		}
+		filename := fmt.Sprintf("chart-%s-%s.%s", chartFileName(chart.Header[0]), chartFileName(chart.Header[1]), format)
+		w.Header().Set("Content-Disposition", `inline; filename="`+filename+`"`)
+		w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+		buf.WriteTo(w)
+	}
+}
+
+// chartFileName keeps the ASCII letters and digits of a unit symbol.
+func chartFileName(symbol string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 128 && (r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, symbol)
+}
+
+// PDF rendering. The document is written by hand with the standard
+// Helvetica font, which every viewer has, so nothing needs embedding.
+
+const (
+	pdfPageWidth   = 595 // A4 in points
+	pdfPageHeight  = 842
+	pdfMargin      = 56
+	pdfRowHeight   = 18
+	pdfColumnWidth = 160
+)
+
+// pdfText encodes a string for a PDF literal string in WinAnsiEncoding,
+// escaping delimiters. Characters outside the encoding become "?".
+func pdfText(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r == '°' || r == '²' || r == '³' || r == 'µ' || r == '·':
+			// Latin-1 characters keep their code in WinAnsiEncoding
+			fmt.Fprintf(&b, "\\%03o", r)
+		case r == 'μ':
+			b.WriteString("\\265")
+		case r == '–':
+			b.WriteString("\\226")
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// writeChartPDF writes the chart as a PDF, starting a new page when a page
+// is full.
+func writeChartPDF(buf *bytes.Buffer, chart chartTable) {
+	rowsPerPage := (pdfPageHeight - 2*pdfMargin - 3*pdfRowHeight) / pdfRowHeight
+	var pages []string
+	for start := 0; start == 0 || start < len(chart.Rows); start += rowsPerPage {
+		end := min(start+rowsPerPage, len(chart.Rows))
+		var c strings.Builder
+		y := pdfPageHeight - pdfMargin
+		fmt.Fprintf(&c, "BT /F2 16 Tf %d %d Td (%s) Tj ET\n", pdfMargin, y, pdfText(chart.Title))
+		y -= 2 * pdfRowHeight
+		fmt.Fprintf(&c, "BT /F2 12 Tf %d %d Td (%s) Tj ET\n", pdfMargin, y, pdfText(chart.Header[0]))
+		fmt.Fprintf(&c, "BT /F2 12 Tf %d %d Td (%s) Tj ET\n", pdfMargin+pdfColumnWidth, y, pdfText(chart.Header[1]))
+		fmt.Fprintf(&c, "0.5 w %d %d m %d %d l S\n", pdfMargin, y-6, pdfMargin+2*pdfColumnWidth, y-6)
+		for i, row := range chart.Rows[start:end] {
+			y -= pdfRowHeight
+			if i%2 == 1 {
+				fmt.Fprintf(&c, "0.93 g %d %d %d %d re f 0 g\n", pdfMargin, y-5, 2*pdfColumnWidth, pdfRowHeight)
+			}
+			fmt.Fprintf(&c, "BT /F1 12 Tf %d %d Td (%s) Tj ET\n", pdfMargin, y, pdfText(row[0]))
+			fmt.Fprintf(&c, "BT /F1 12 Tf %d %d Td (%s) Tj ET\n", pdfMargin+pdfColumnWidth, y, pdfText(row[1]))
+		}
+		pages = append(pages, c.String())
+		if end == len(chart.Rows) {
+			break
+		}
+	}
+
+	// Objects: 1 catalog, 2 page tree, 3-4 fonts, then a page and its
+	// content stream for each page
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 6+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+}
+
+// PNG rendering uses a built-in 5×7 bitmap font, drawn at chartScale.
+
+const (
+	chartScale   = 2
+	glyphWidth   = 6 * chartScale // 5 pixels and a gap
+	glyphHeight  = 7 * chartScale
+	chartPadding = 12 * chartScale
+	chartRow     = 12 * chartScale
+)
+
+// renderChartPNG draws the chart as an image.
+func renderChartPNG(chart chartTable) image.Image {
+	widest := [2]int{len([]rune(chart.Header[0])), len([]rune(chart.Header[1]))}
+	for _, row := range chart.Rows {
+		for col, cell := range row {
+			widest[col] = max(widest[col], len([]rune(cell)))
+		}
+	}
+	colWidth := [2]int{(widest[0] + 4) * glyphWidth, (widest[1] + 2) * glyphWidth}
+	width := max(2*chartPadding+colWidth[0]+colWidth[1], 2*chartPadding+len([]rune(chart.Title))*glyphWidth)
+	height := 2*chartPadding + (len(chart.Rows)+3)*chartRow
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	white, black, stripe := color.RGBA{255, 255, 255, 255}, color.RGBA{0, 0, 0, 255}, color.RGBA{238, 238, 238, 255}
+	fillRect(img, img.Bounds(), white)
+
+	y := chartPadding
+	drawText(img, chartPadding, y, chart.Title, black)
+	y += 2 * chartRow
+	drawText(img, chartPadding, y, chart.Header[0], black)
+	drawText(img, chartPadding+colWidth[0], y, chart.Header[1], black)
+	fillRect(img, image.Rect(chartPadding, y+glyphHeight+chartScale*2, width-chartPadding, y+glyphHeight+chartScale*3), black)
+	for i, row := range chart.Rows {
+		y += chartRow
+		if i%2 == 1 {
+			fillRect(img, image.Rect(chartPadding, y-chartScale*2, width-chartPadding, y+glyphHeight+chartScale*3), stripe)
+		}
+		drawText(img, chartPadding, y, row[0], black)
+		drawText(img, chartPadding+colWidth[0], y, row[1], black)
+	}
+	return img
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawText draws s with its top-left corner at (x, y).
+func drawText(img *image.RGBA, x, y int, s string, c color.RGBA) {
+	for _, r := range s {
+		rows, ok := glyphs[r]
+		if !ok {
+			rows = glyphs['?']
+		}
+		for gy, bits := range rows {
+			for gx := 0; gx < 5; gx++ {
+				if bits[gx] == '1' {
+					fillRect(img, image.Rect(x+gx*chartScale, y+gy*chartScale, x+(gx+1)*chartScale, y+(gy+1)*chartScale), c)
+				}
+			}
+		}
+		x += glyphWidth
+	}
+}
+
+// glyphs is a 5×7 bitmap font: seven rows of five pixels per character.
+var glyphs = func() map[rune][7]string {
+	font := map[rune]string{
+		'0': "01110 10001 10011 10101 11001 10001 01110",
+		'1': "00100 01100 00100 00100 00100 00100 01110",
+		'2': "01110 10001 00001 00010 00100 01000 11111",
+		'3': "11111 00010 00100 00010 00001 10001 01110",
+		'4': "00010 00110 01010 10010 11111 00010 00010",
+		'5': "11111 10000 11110 00001 00001 10001 01110",
+		'6': "00110 01000 10000 11110 10001 10001 01110",
+		'7': "11111 00001 00010 00100 01000 01000 01000",
+		'8': "01110 10001 10001 01110 10001 10001 01110",
+		'9': "01110 10001 10001 01111 00001 00010 01100",
+		'A': "01110 10001 10001 11111 10001 10001 10001",
+		'B': "11110 10001 10001 11110 10001 10001 11110",
+		'C': "01110 10001 10000 10000 10000 10001 01110",
+		'D': "11100 10010 10001 10001 10001 10010 11100",
+		'E': "11111 10000 10000 11110 10000 10000 11111",
+		'F': "11111 10000 10000 11110 10000 10000 10000",
+		'G': "01110 10001 10000 10111 10001 10001 01111",
+		'H': "10001 10001 10001 11111 10001 10001 10001",
+		'I': "01110 00100 00100 00100 00100 00100 01110",
+		'J': "00111 00010 00010 00010 00010 10010 01100",
+		'K': "10001 10010 10100 11000 10100 10010 10001",
+		'L': "10000 10000 10000 10000 10000 10000 11111",
+		'M': "10001 11011 10101 10101 10001 10001 10001",
+		'N': "10001 10001 11001 10101 10011 10001 10001",
+		'O': "01110 10001 10001 10001 10001 10001 01110",
+		'P': "11110 10001 10001 11110 10000 10000 10000",
+		'Q': "01110 10001 10001 10001 10101 10010 01101",
+		'R': "11110 10001 10001 11110 10100 10010 10001",
+		'S': "01111 10000 10000 01110 00001 00001 11110",
+		'T': "11111 00100 00100 00100 00100 00100 00100",
+		'U': "10001 10001 10001 10001 10001 10001 01110",
+		'V': "10001 10001 10001 10001 10001 01010 00100",
+		'W': "10001 10001 10001 10101 10101 10101 01010",
+		'X': "10001 10001 01010 00100 01010 10001 10001",
+		'Y': "10001 10001 10001 01010 00100 00100 00100",
+		'Z': "11111 00001 00010 00100 01000 10000 11111",
+		'a': "00000 00000 01110 00001 01111 10001 01111",
+		'b': "10000 10000 10110 11001 10001 10001 11110",
+		'c': "00000 00000 01110 10000 10000 10001 01110",
+		'd': "00001 00001 01101 10011 10001 10001 01111",
+		'e': "00000 00000 01110 10001 11111 10000 01110",
+		'f': "00110 01001 01000 11100 01000 01000 01000",
+		'g': "00000 01111 10001 10001 01111 00001 01110",
+		'h': "10000 10000 10110 11001 10001 10001 10001",
+		'i': "00100 00000 01100 00100 00100 00100 01110",
+		'j': "00010 00000 00110 00010 00010 10010 01100",
+		'k': "10000 10000 10010 10100 11000 10100 10010",
+		'l': "01100 00100 00100 00100 00100 00100 01110",
+		'm': "00000 00000 11010 10101 10101 10001 10001",
+		'n': "00000 00000 10110 11001 10001 10001 10001",
+		'o': "00000 00000 01110 10001 10001 10001 01110",
+		'p': "00000 00000 11110 10001 11110 10000 10000",
+		'q': "00000 00000 01101 10011 01111 00001 00001",
+		'r': "00000 00000 10110 11001 10000 10000 10000",
+		's': "00000 00000 01110 10000 01110 00001 11110",
+		't': "01000 01000 11100 01000 01000 01001 00110",
+		'u': "00000 00000 10001 10001 10001 10011 01101",
+		'v': "00000 00000 10001 10001 10001 01010 00100",
+		'w': "00000 00000 10001 10001 10101 10101 01010",
+		'x': "00000 00000 10001 01010 00100 01010 10001",
+		'y': "00000 00000 10001 10001 01111 00001 01110",
+		'z': "00000 00000 11111 00010 00100 01000 11111",
+		' ': "00000 00000 00000 00000 00000 00000 00000",
+		'.': "00000 00000 00000 00000 00000 01100 01100",
+		',': "00000 00000 00000 00000 01100 00100 01000",
+		'-': "00000 00000 00000 11111 00000 00000 00000",
+		'+': "00000 00100 00100 11111 00100 00100 00000",
+		'/': "00000 00001 00010 00100 01000 10000 00000",
+		'(': "00010 00100 01000 01000 01000 00100 00010",
+		')': "01000 00100 00010 00010 00010 00100 01000",
+		'#': "01010 01010 11111 01010 11111 01010 01010",
+		'%': "11000 11001 00010 00100 01000 10011 00011",
+		'=': "00000 00000 11111 00000 11111 00000 00000",
+		':': "00000 01100 01100 00000 01100 01100 00000",
+		'_': "00000 00000 00000 00000 00000 00000 11111",
+		'*': "00000 00100 10101 01110 10101 00100 00000",
+		'?': "01110 10001 00001 00010 00100 00000 00100",
+		'°': "01100 10010 10010 01100 00000 00000 00000",
+		'²': "01100 00010 00100 01000 01110 00000 00000",
+		'³': "01100 00010 00100 00010 01100 00000 00000",
+		'µ': "00000 00000 10001 10001 10011 11101 10000",
+		'·': "00000 00000 00000 00100 00000 00000 00000",
+	}
+	font['μ'] = font['µ']
+	font['–'] = font['-']
+
+	parsed := make(map[rune][7]string, len(font))
+	for r, rows := range font {
+		var glyph [7]string
+		copy(glyph[:], strings.Fields(rows))
+		parsed[r] = glyph
+	}
+	return parsed
+}()
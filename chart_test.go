@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+func TestBuildChart(t *testing.T) {
+	uc := converter.NewUnitConverter()
+	chart, err := buildChart(context.Background(), uc, 0, 1000, 0, "m", "km")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A zero step gives ten steps, both ends included
+	if len(chart.Rows) != 11 || chart.Rows[5] != [2]string{"500", "0.5000"} {
+		t.Errorf("rows = %q", chart.Rows)
+	}
+}
+
+func TestChartHandlerInvalidRange(t *testing.T) {
+	handler := chartHandler(converter.NewUnitConverter(), "png")
+	for _, query := range []string{
+		"from=m&to=km&min=-1e308&max=1e308",
+		"from=m&to=km&min=-1e308&max=1e308&step=1e307",
+		"from=m&to=km&min=0&max=1000&step=1",
+		"from=m&to=km&min=5&max=1",
+	} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/api/chart.png?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,777 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// Config holds the server settings. It is assembled by loadConfig from, in
+// increasing order of precedence: defaults, a config file, GOVERTER_*
+// environment variables and command-line flags.
+type Config struct {
+	Addr       string   // Listen address: ":8080", "unix:/path/to.sock" or "systemd"
+	BasePath   string   // URL prefix the app is mounted at behind a proxy, e.g. "/tools/convert"
+	TLSCert    string   // TLS certificate file; serves HTTPS together with TLSKey
+	TLSKey     string   // TLS private key file
+	AdminToken string   // Bearer token for /admin endpoints; empty disables them
+	UnitFiles  []string // Unit definition files layered over the built-in units
+	UnitPacks  []string // Optional unit packs to enable, e.g. "imperial"
+
+	SocketMode os.FileMode // Permissions of the socket file when listening on "unix:/path"
+
+	SlackSigningSecret string // Enables /integrations/slack when set
+
+	// API keys and their default limits; zero limits are unlimited
+	APIKeys         []string // "name:secret" items, optionally with ":daily:monthly:per_minute" limits
+	APIRequireKey   bool     // Refuse API requests without a key
+	APIDailyQuota   int64    // Requests per key per UTC day
+	APIMonthlyQuota int64    // Requests per key per UTC month
+	APIRateLimit    int64    // Requests per key per minute
+
+	// Namespaces with their own custom units
+	Namespaces    []string // "name" or "name=unit file" items
+	NamespaceKeys []string // "key=namespace" items assigning API keys to namespaces
+
+	// Audit log of administrative actions
+	AuditFile        string        // JSON Lines file; empty keeps the log in memory
+	AuditRetention   time.Duration // Age at which entries are pruned; zero keeps them forever
+	AuditConversions bool          // Also audit every conversion
+
+	WebhooksFile string // JSON Lines file of registered webhooks; empty keeps them in memory
+
+	// Server log
+	LogFile     string // File the log is written to; empty logs to stderr
+	LogMaxBytes int64  // Size past which the log-rotate job rotates the file; zero never rotates
+	LogBackups  int64  // Rotated log files kept
+
+	Precision map[string]int // Decimal places shown per dimension, e.g. "angle" -> 6
+
+	ReferencePressure float64 // dB SPL reference in Pa
+
+	// HTTP server limits
+	ReadHeaderTimeout time.Duration // Time allowed to read request headers
+	ReadTimeout       time.Duration // Time allowed to read the whole request, body included
+	WriteTimeout      time.Duration // Time allowed to write the response
+	IdleTimeout       time.Duration // How long keep-alive connections stay open between requests
+	MaxHeaderBytes    int           // Maximum size of request headers
+	MaxBodyBytes      int64         // Maximum size of request bodies
+}
+
+// defaultConfig returns the settings used when nothing is configured.
+func defaultConfig() Config {
+	return Config{
+		Addr:              ":8080",
+		SocketMode:        0660,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+		MaxHeaderBytes:    64 << 10,
+		MaxBodyBytes:      1 << 20,
+		ReferencePressure: converter.DefaultReferencePressure,
+		AuditRetention:    90 * 24 * time.Hour,
+		LogMaxBytes:       10 << 20,
+		LogBackups:        5,
+	}
+}
+
+// setting binds one configuration value to its config file key,
+// environment variable and command-line flag. List settings use getList
+// and setList instead of get and set, so items are never joined into one
+// string and split again.
+type setting struct {
+	key     string // Config file key, "<section>.<name>"
+	env     string
+	flag    string
+	usage   string
+	secret  bool // Redacted by "config print"
+	bool    bool // A flag that needs no value, printed unquoted by "config print"
+	table   bool // "name=value" items the config file may also give as a [section.name] table
+	get     func(c *Config) string
+	set     func(c *Config, v string) error
+	getList func(c *Config) []string
+	setList func(c *Config, items []string) error
+}
+
+// configValue is a setting value as given in one source: a single value,
+// or the items of a config file array.
+type configValue struct {
+	text  string
+	items []string
+	array bool
+}
+
+// listItems returns the items of a value for a list setting. The
+// environment, flags and plain config file strings give comma-separated
+// items; only config file arrays can hold items containing commas.
+func (v configValue) listItems() []string {
+	if v.array {
+		return v.items
+	}
+	var items []string
+	for _, item := range strings.Split(v.text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// apply sets s from v.
+func (s setting) apply(c *Config, v configValue) error {
+	if s.setList != nil {
+		return s.setList(c, v.listItems())
+	}
+	if v.array {
+		return errors.New("expected a single value, not an array")
+	}
+	return s.set(c, v.text)
+}
+
+func stringSetting(key, env, flagName, usage string, field func(c *Config) *string) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage,
+		get: func(c *Config) string { return *field(c) },
+		set: func(c *Config, v string) error { *field(c) = v; return nil },
+	}
+}
+
+func secretSetting(key, env, flagName, usage string, field func(c *Config) *string) setting {
+	s := stringSetting(key, env, flagName, usage, field)
+	s.secret = true
+	return s
+}
+
+func listSetting(key, env, flagName, usage string, field func(c *Config) *[]string) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage,
+		getList: func(c *Config) []string { return *field(c) },
+		setList: func(c *Config, items []string) error { *field(c) = items; return nil },
+	}
+}
+
+func secretListSetting(key, env, flagName, usage string, field func(c *Config) *[]string) setting {
+	s := listSetting(key, env, flagName, usage, field)
+	s.secret = true
+	return s
+}
+
+func boolSetting(key, env, flagName, usage string, field func(c *Config) *bool) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage, bool: true,
+		get: func(c *Config) string { return strconv.FormatBool(*field(c)) },
+		set: func(c *Config, v string) error {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid boolean %q", v)
+			}
+			*field(c) = b
+			return nil
+		},
+	}
+}
+
+func durationSetting(key, env, flagName, usage string, field func(c *Config) *time.Duration) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage,
+		get: func(c *Config) string { return field(c).String() },
+		set: func(c *Config, v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return fmt.Errorf("invalid duration %q", v)
+			}
+			*field(c) = d
+			return nil
+		},
+	}
+}
+
+func intSetting(key, env, flagName, usage string, field func(c *Config) *int64) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage,
+		get: func(c *Config) string { return strconv.FormatInt(*field(c), 10) },
+		set: func(c *Config, v string) error {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", v)
+			}
+			*field(c) = n
+			return nil
+		},
+	}
+}
+
+func floatSetting(key, env, flagName, usage string, field func(c *Config) *float64) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage,
+		get: func(c *Config) string { return strconv.FormatFloat(*field(c), 'g', -1, 64) },
+		set: func(c *Config, v string) error {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", v)
+			}
+			*field(c) = f
+			return nil
+		},
+	}
+}
+
+// precisionSetting parses "dimension=places" items into a map. In the
+// config file they may also be a table, [format.precision] with angle = 6.
+func precisionSetting(key, env, flagName, usage string, field func(c *Config) *map[string]int) setting {
+	return setting{
+		key: key, env: env, flag: flagName, usage: usage, table: true,
+		getList: func(c *Config) []string {
+			items := make([]string, 0, len(*field(c)))
+			for dimension, places := range *field(c) {
+				items = append(items, dimension+"="+strconv.Itoa(places))
+			}
+			sort.Strings(items)
+			return items
+		},
+		setList: func(c *Config, items []string) error {
+			m := make(map[string]int)
+			for _, item := range items {
+				dimension, places, ok := strings.Cut(item, "=")
+				n, err := strconv.Atoi(strings.TrimSpace(places))
+				if !ok || err != nil || strings.TrimSpace(dimension) == "" {
+					return fmt.Errorf("invalid precision %q, expected dimension=places", item)
+				}
+				m[strings.TrimSpace(dimension)] = n
+			}
+			*field(c) = m
+			return nil
+		},
+	}
+}
+
+// settings lists every configurable value, in "config print" order.
+var settings = []setting{
+	stringSetting("server.addr", "GOVERTER_ADDR", "addr", "listen address: host:port, unix:/path/to.sock or systemd",
+		func(c *Config) *string { return &c.Addr }),
+	{
+		key: "server.socket_mode", env: "GOVERTER_SOCKET_MODE", flag: "socket-mode", usage: "octal permissions of the Unix socket",
+		get: func(c *Config) string { return fmt.Sprintf("%04o", uint32(c.SocketMode)) },
+		set: func(c *Config, v string) error {
+			mode, err := strconv.ParseUint(strings.TrimPrefix(v, "0o"), 8, 32)
+			if err != nil || mode > 0777 {
+				return fmt.Errorf("invalid octal mode %q", v)
+			}
+			c.SocketMode = os.FileMode(mode)
+			return nil
+		},
+	},
+	stringSetting("server.base_path", "GOVERTER_BASE_PATH", "base-path", "URL prefix the app is mounted at, e.g. /tools/convert",
+		func(c *Config) *string { return &c.BasePath }),
+	stringSetting("server.tls_cert", "GOVERTER_TLS_CERT", "tls-cert", "TLS certificate file",
+		func(c *Config) *string { return &c.TLSCert }),
+	stringSetting("server.tls_key", "GOVERTER_TLS_KEY", "tls-key", "TLS private key file",
+		func(c *Config) *string { return &c.TLSKey }),
+	durationSetting("server.read_header_timeout", "GOVERTER_READ_HEADER_TIMEOUT", "read-header-timeout", "time allowed to read request headers",
+		func(c *Config) *time.Duration { return &c.ReadHeaderTimeout }),
+	durationSetting("server.read_timeout", "GOVERTER_READ_TIMEOUT", "read-timeout", "time allowed to read a whole request",
+		func(c *Config) *time.Duration { return &c.ReadTimeout }),
+	durationSetting("server.write_timeout", "GOVERTER_WRITE_TIMEOUT", "write-timeout", "time allowed to write a response",
+		func(c *Config) *time.Duration { return &c.WriteTimeout }),
+	durationSetting("server.idle_timeout", "GOVERTER_IDLE_TIMEOUT", "idle-timeout", "keep-alive idle timeout",
+		func(c *Config) *time.Duration { return &c.IdleTimeout }),
+	{
+		key: "server.max_header_bytes", env: "GOVERTER_MAX_HEADER_BYTES", flag: "max-header-bytes", usage: "maximum request header size",
+		get: func(c *Config) string { return strconv.Itoa(c.MaxHeaderBytes) },
+		set: func(c *Config, v string) error {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid number %q", v)
+			}
+			c.MaxHeaderBytes = n
+			return nil
+		},
+	},
+	intSetting("server.max_body_bytes", "GOVERTER_MAX_BODY_BYTES", "max-body-bytes", "maximum request body size",
+		func(c *Config) *int64 { return &c.MaxBodyBytes }),
+	secretSetting("admin.token", "GOVERTER_ADMIN_TOKEN", "admin-token", "bearer token for /admin endpoints",
+		func(c *Config) *string { return &c.AdminToken }),
+	secretListSetting("api.keys", "GOVERTER_API_KEYS", "api-keys", "comma-separated API keys as name:secret[:daily:monthly:per_minute]",
+		func(c *Config) *[]string { return &c.APIKeys }),
+	boolSetting("api.require_key", "GOVERTER_API_REQUIRE_KEY", "api-require-key", "refuse API requests without a key",
+		func(c *Config) *bool { return &c.APIRequireKey }),
+	intSetting("api.daily_quota", "GOVERTER_API_DAILY_QUOTA", "api-daily-quota", "requests per API key per day (0 for unlimited)",
+		func(c *Config) *int64 { return &c.APIDailyQuota }),
+	intSetting("api.monthly_quota", "GOVERTER_API_MONTHLY_QUOTA", "api-monthly-quota", "requests per API key per month (0 for unlimited)",
+		func(c *Config) *int64 { return &c.APIMonthlyQuota }),
+	intSetting("api.rate_limit", "GOVERTER_API_RATE_LIMIT", "api-rate-limit", "requests per API key per minute (0 for unlimited)",
+		func(c *Config) *int64 { return &c.APIRateLimit }),
+	stringSetting("audit.file", "GOVERTER_AUDIT_FILE", "audit-file", "audit log file (JSON Lines)",
+		func(c *Config) *string { return &c.AuditFile }),
+	durationSetting("audit.retention", "GOVERTER_AUDIT_RETENTION", "audit-retention", "how long audit entries are kept (0 for forever)",
+		func(c *Config) *time.Duration { return &c.AuditRetention }),
+	boolSetting("audit.conversions", "GOVERTER_AUDIT_CONVERSIONS", "audit-conversions", "also audit every conversion",
+		func(c *Config) *bool { return &c.AuditConversions }),
+	stringSetting("webhooks.file", "GOVERTER_WEBHOOKS_FILE", "webhooks-file", "registered webhooks file (JSON Lines)",
+		func(c *Config) *string { return &c.WebhooksFile }),
+	stringSetting("log.file", "GOVERTER_LOG_FILE", "log-file", "log file (stderr when empty)",
+		func(c *Config) *string { return &c.LogFile }),
+	intSetting("log.max_bytes", "GOVERTER_LOG_MAX_BYTES", "log-max-bytes", "log file size at which it is rotated (0 to never rotate)",
+		func(c *Config) *int64 { return &c.LogMaxBytes }),
+	intSetting("log.backups", "GOVERTER_LOG_BACKUPS", "log-backups", "rotated log files kept",
+		func(c *Config) *int64 { return &c.LogBackups }),
+	listSetting("namespaces.names", "GOVERTER_NAMESPACES", "namespaces", "comma-separated namespaces as name or name=unit file",
+		func(c *Config) *[]string { return &c.Namespaces }),
+	listSetting("namespaces.keys", "GOVERTER_NAMESPACE_KEYS", "namespace-keys", "comma-separated key=namespace items assigning API keys to namespaces",
+		func(c *Config) *[]string { return &c.NamespaceKeys }),
+	listSetting("units.files", "GOVERTER_UNIT_FILES", "unit-files", "comma-separated unit definition files",
+		func(c *Config) *[]string { return &c.UnitFiles }),
+	listSetting("units.packs", "GOVERTER_UNIT_PACKS", "unit-packs", "comma-separated optional unit packs to enable",
+		func(c *Config) *[]string { return &c.UnitPacks }),
+	floatSetting("units.reference_pressure", "GOVERTER_REFERENCE_PRESSURE", "reference-pressure", "dB SPL reference pressure in Pa",
+		func(c *Config) *float64 { return &c.ReferencePressure }),
+	secretSetting("slack.signing_secret", "GOVERTER_SLACK_SIGNING_SECRET", "slack-signing-secret", "Slack app signing secret",
+		func(c *Config) *string { return &c.SlackSigningSecret }),
+	precisionSetting("format.precision", "GOVERTER_PRECISION", "precision", "decimal places per dimension, e.g. angle=6,data_storage=2",
+		func(c *Config) *map[string]int { return &c.Precision }),
+}
+
+// configSources records where each setting's effective value came from.
+type configSources map[string]string
+
+// loadConfig assembles the configuration from defaults, the config file
+// named by -config or GOVERTER_CONFIG, the environment and args, then
+// validates it.
+func loadConfig(args []string, getenv func(string) string) (Config, configSources, error) {
+	cfg := defaultConfig()
+	sources := make(configSources, len(settings))
+	for _, s := range settings {
+		sources[s.key] = "default"
+	}
+
+	// Flags are parsed first to find the config file, but applied last
+	fs := flag.NewFlagSet("goverter", flag.ContinueOnError)
+	configPath := fs.String("config", getenv("GOVERTER_CONFIG"), "config file (TOML)")
+	flagValues := make(map[string]configValue)
+	for _, s := range settings {
+		setFlag := func(v string) error {
+			flagValues[s.key] = configValue{text: v}
+			return nil
+		}
+		if s.bool {
+			fs.BoolFunc(s.flag, s.usage, setFlag)
+		} else {
+			fs.Func(s.flag, s.usage, setFlag)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if *configPath != "" {
+		data, err := os.ReadFile(*configPath)
+		if err != nil {
+			return Config{}, nil, err
+		}
+		values, err := parseConfigFile(data)
+		if err != nil {
+			return Config{}, nil, fmt.Errorf("%s: %w", *configPath, err)
+		}
+		if err := applySettings(&cfg, sources, values, "file", func(s setting) string { return s.key }); err != nil {
+			return Config{}, nil, fmt.Errorf("%s: %w", *configPath, err)
+		}
+	}
+
+	envValues := make(map[string]configValue)
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			envValues[s.key] = configValue{text: v}
+		}
+	}
+	if err := applySettings(&cfg, sources, envValues, "env", func(s setting) string { return s.env }); err != nil {
+		return Config{}, nil, err
+	}
+	if err := applySettings(&cfg, sources, flagValues, "flag", func(s setting) string { return "-" + s.flag }); err != nil {
+		return Config{}, nil, err
+	}
+
+	if err := cfg.validate(); err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, sources, nil
+}
+
+// applySettings sets values keyed by setting key, rejecting unknown keys.
+// name gives the spelling of a setting used in error messages.
+func applySettings(cfg *Config, sources configSources, values map[string]configValue, source string, name func(setting) string) error {
+	known := make(map[string]bool, len(values))
+	for _, s := range settings {
+		v, ok := values[s.key]
+		if !ok {
+			continue
+		}
+		known[s.key] = true
+		if err := s.apply(cfg, v); err != nil {
+			return fmt.Errorf("%s: %w", name(s), err)
+		}
+		sources[s.key] = source
+	}
+
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown settings: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// validate checks the configuration for values the server can't run with.
+func (c Config) validate() error {
+	var problems []string
+	if err := validListenAddr(c.Addr); err != nil {
+		problems = append(problems, "server.addr "+err.Error())
+	}
+	if _, err := NormalizeBasePath(c.BasePath); err != nil {
+		problems = append(problems, "server.base_path: "+err.Error())
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		problems = append(problems, "server.tls_cert and server.tls_key must be set together")
+	}
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"server.read_header_timeout", c.ReadHeaderTimeout},
+		{"server.read_timeout", c.ReadTimeout},
+		{"server.write_timeout", c.WriteTimeout},
+		{"server.idle_timeout", c.IdleTimeout},
+		{"audit.retention", c.AuditRetention},
+	} {
+		if d.value < 0 {
+			problems = append(problems, d.key+" must not be negative")
+		}
+	}
+	if c.MaxHeaderBytes <= 0 {
+		problems = append(problems, "server.max_header_bytes must be positive")
+	}
+	if c.MaxBodyBytes <= 0 {
+		problems = append(problems, "server.max_body_bytes must be positive")
+	}
+	if !(c.ReferencePressure > 0) || math.IsInf(c.ReferencePressure, 0) {
+		problems = append(problems, "units.reference_pressure must be a positive number")
+	}
+	for _, q := range []struct {
+		key   string
+		value int64
+	}{
+		{"api.daily_quota", c.APIDailyQuota},
+		{"api.monthly_quota", c.APIMonthlyQuota},
+		{"api.rate_limit", c.APIRateLimit},
+		{"log.max_bytes", c.LogMaxBytes},
+		{"log.backups", c.LogBackups},
+	} {
+		if q.value < 0 {
+			problems = append(problems, q.key+" must not be negative")
+		}
+	}
+	if _, err := c.apiKeys(); err != nil {
+		problems = append(problems, "api.keys: "+err.Error())
+	}
+	namespaces, err := parseNamespaces(c.Namespaces)
+	if err != nil {
+		problems = append(problems, "namespaces.names: "+err.Error())
+	}
+	if _, err := parseNamespaceKeys(c.NamespaceKeys); err != nil {
+		problems = append(problems, "namespaces.keys: "+err.Error())
+	} else {
+		keys, _ := c.apiKeys()
+		for _, item := range c.NamespaceKeys {
+			key, name, _ := strings.Cut(item, "=")
+			key, name = strings.TrimSpace(key), strings.TrimSpace(name)
+			if !slices.ContainsFunc(keys, func(k APIKey) bool { return k.Name == key }) {
+				problems = append(problems, fmt.Sprintf("namespaces.keys: unknown API key %q", key))
+			}
+			if !slices.ContainsFunc(namespaces, func(ns NamespaceConfig) bool { return ns.Name == name }) {
+				problems = append(problems, fmt.Sprintf("namespaces.keys: unknown namespace %q", name))
+			}
+		}
+	}
+	if c.APIRequireKey && len(c.APIKeys) == 0 {
+		problems = append(problems, "api.require_key needs api.keys")
+	}
+	available := converter.OptionalPacks()
+	for _, name := range c.UnitPacks {
+		if !slices.Contains(available, name) {
+			problems = append(problems, fmt.Sprintf("units.packs: unknown pack %q (available: %s)", name, strings.Join(available, ", ")))
+		}
+	}
+	for dimension, places := range c.Precision {
+		if places < 0 || places > converter.MaxPrecision {
+			problems = append(problems, fmt.Sprintf("format.precision for %s must be between 0 and %d", dimension, converter.MaxPrecision))
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// apiKeys parses the configured API keys, applying the default limits.
+func (c Config) apiKeys() ([]APIKey, error) {
+	defaults := APIKey{Daily: c.APIDailyQuota, Monthly: c.APIMonthlyQuota, PerMinute: c.APIRateLimit}
+	keys := make([]APIKey, 0, len(c.APIKeys))
+	names := make(map[string]bool, len(c.APIKeys))
+	for _, definition := range c.APIKeys {
+		key, err := ParseAPIKey(definition, defaults)
+		if err != nil {
+			return nil, err
+		}
+		if names[key.Name] {
+			return nil, fmt.Errorf("duplicate API key name %q", key.Name)
+		}
+		names[key.Name] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// parseConfigFile reads the TOML subset used by goverter config files:
+// [section] headers, key = value pairs with strings, numbers, booleans or
+// single-line arrays of them, and # comments. Values are keyed by
+// "<section>.<key>"; arrays keep their items apart.
+func parseConfigFile(data []byte) (map[string]configValue, error) {
+	values := make(map[string]configValue)
+	section := ""
+	table := false // The section is a table setting, whose keys become its items
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("line %d: malformed section header", lineNo)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			table = isTableSetting(section)
+			if table {
+				if _, dup := values[section]; dup {
+					return nil, fmt.Errorf("line %d: %s set twice", lineNo, section)
+				}
+				values[section] = configValue{array: true, items: []string{}}
+			}
+			continue
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if table {
+			if err := addTableItem(values, section, key, strings.TrimSpace(raw)); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			continue
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		value, err := parseConfigValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("line %d: %s set twice", lineNo, key)
+		}
+		values[key] = value
+	}
+	return values, scanner.Err()
+}
+
+// isTableSetting reports whether key names a setting that the config file
+// may give as a table.
+func isTableSetting(key string) bool {
+	for _, s := range settings {
+		if s.key == key {
+			return s.table
+		}
+	}
+	return false
+}
+
+// addTableItem adds "name = value" from the table of a setting as its
+// "name=value" item.
+func addTableItem(values map[string]configValue, key, name, raw string) error {
+	if strings.HasPrefix(raw, "[") {
+		return fmt.Errorf("%s.%s: expected a single value, not an array", key, name)
+	}
+	text, err := parseConfigScalar(raw)
+	if err != nil {
+		return err
+	}
+	value := values[key]
+	for _, item := range value.items {
+		if existing, _, _ := strings.Cut(item, "="); existing == name {
+			return fmt.Errorf("%s.%s set twice", key, name)
+		}
+	}
+	value.items = append(value.items, name+"="+text)
+	values[key] = value
+	return nil
+}
+
+// stripComment removes a trailing # comment that isn't inside a string.
+func stripComment(line string) string {
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case '#':
+			if !inString {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// parseConfigValue converts a TOML value to a configValue.
+func parseConfigValue(raw string) (configValue, error) {
+	if !strings.HasPrefix(raw, "[") {
+		text, err := parseConfigScalar(raw)
+		return configValue{text: text}, err
+	}
+	if !strings.HasSuffix(raw, "]") {
+		return configValue{}, fmt.Errorf("arrays must be on a single line")
+	}
+	value := configValue{array: true, items: []string{}}
+	inner := strings.TrimSpace(raw[1 : len(raw)-1])
+	if inner == "" {
+		return value, nil
+	}
+	for _, item := range splitArray(inner) {
+		item = strings.TrimSpace(item)
+		if strings.HasPrefix(item, "[") {
+			return configValue{}, fmt.Errorf("nested arrays are not supported")
+		}
+		text, err := parseConfigScalar(item)
+		if err != nil {
+			return configValue{}, err
+		}
+		value.items = append(value.items, text)
+	}
+	return value, nil
+}
+
+// parseConfigScalar converts a TOML string, number or boolean to its
+// string form.
+func parseConfigScalar(raw string) (string, error) {
+	switch {
+	case raw == "":
+		return "", fmt.Errorf("missing value")
+	case strings.HasPrefix(raw, `"`):
+		return strconv.Unquote(raw)
+	default:
+		// Bare numbers and booleans are passed through for the setting to parse
+		return raw, nil
+	}
+}
+
+// splitArray splits array items on commas outside of strings, ignoring a
+// trailing comma.
+func splitArray(s string) []string {
+	var items []string
+	inString, start := false, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case ',':
+			if !inString {
+				items = append(items, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if rest := strings.TrimSpace(s[start:]); rest != "" {
+		items = append(items, rest)
+	}
+	return items
+}
+
+// printConfig writes the effective configuration in config file syntax,
+// noting where each value came from. Secrets are redacted.
+func printConfig(w io.Writer, cfg Config, sources configSources) {
+	section := ""
+	for _, s := range settings {
+		sec, name, _ := strings.Cut(s.key, ".")
+		if sec != section {
+			if section != "" {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "[%s]\n", sec)
+			section = sec
+		}
+
+		var rendered string
+		if s.getList != nil {
+			rendered = renderList(s.getList(&cfg), s.secret)
+		} else {
+			rendered = renderValue(s.get(&cfg), s)
+		}
+		fmt.Fprintf(w, "%s = %s # %s\n", name, rendered, sources[s.key])
+	}
+}
+
+// renderList writes list items as a config file array.
+func renderList(items []string, secret bool) string {
+	if secret && len(items) > 0 {
+		return `"<redacted>"`
+	}
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = strconv.Quote(item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// renderValue writes a single value in config file syntax.
+func renderValue(value string, s setting) string {
+	switch {
+	case s.secret && value != "":
+		return `"<redacted>"`
+	case s.bool:
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return strconv.Quote(value)
+}
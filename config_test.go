@@ -0,0 +1,236 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseConfigFile(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]configValue
+	}{
+		{
+			name:  "sections and scalars",
+			input: "[server]\naddr = \":9090\"\nmax_body_bytes = 2048\n\n[api]\nrequire_key = true\n",
+			want: map[string]configValue{
+				"server.addr":           {text: ":9090"},
+				"server.max_body_bytes": {text: "2048"},
+				"api.require_key":       {text: "true"},
+			},
+		},
+		{
+			name:  "comments",
+			input: "# Server settings\n[server]\naddr = \"host#1:80\" # not part of the value\n",
+			want:  map[string]configValue{"server.addr": {text: "host#1:80"}},
+		},
+		{
+			name:  "array items keep their commas",
+			input: "[units]\nfiles = [\"lab, shared.json\", \"extra.json\",]\n",
+			want: map[string]configValue{
+				"units.files": {array: true, items: []string{"lab, shared.json", "extra.json"}},
+			},
+		},
+		{
+			name:  "empty array",
+			input: "[units]\npacks = []\n",
+			want:  map[string]configValue{"units.packs": {array: true, items: []string{}}},
+		},
+		{
+			name:  "table",
+			input: "[format.precision]\nangle = 6\ndata_storage = 2 # whole bytes\n\n[server]\naddr = \":9090\"\n",
+			want: map[string]configValue{
+				"format.precision": {array: true, items: []string{"angle=6", "data_storage=2"}},
+				"server.addr":      {text: ":9090"},
+			},
+		},
+		{
+			name:  "escaped quotes",
+			input: "[admin]\ntoken = \"a\\\"b\"\n",
+			want:  map[string]configValue{"admin.token": {text: `a"b`}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConfigFile([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("parseConfigFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConfigFile = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigFileErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"malformed section", "[server\n", "line 1: malformed section header"},
+		{"missing equals", "[server]\naddr\n", "line 2: expected key = value"},
+		{"missing value", "[server]\naddr =\n", "line 2: missing value"},
+		{"duplicate key", "[server]\naddr = \":1\"\naddr = \":2\"\n", "line 3: server.addr set twice"},
+		{"multi-line array", "[units]\nfiles = [\"a.json\",\n", "line 2: arrays must be on a single line"},
+		{"nested array", "[units]\nfiles = [[\"a.json\"]]\n", "line 2: nested arrays are not supported"},
+		{"bad string", "[server]\naddr = \"unterminated\n", "line 2: invalid syntax"},
+		{"duplicate table key", "[format.precision]\nangle = 6\nangle = 2\n", "line 3: format.precision.angle set twice"},
+		{"table and array", "[format]\nprecision = [\"angle=6\"]\n[format.precision]\nangle = 2\n", "line 3: format.precision set twice"},
+		{"array in a table", "[format.precision]\nangle = [6]\n", "line 2: format.precision.angle: expected a single value, not an array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseConfigFile([]byte(tt.input))
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("parseConfigFile error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// writeConfigFile writes content to a config file in a test directory.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "goverter.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+[server]
+addr = ":7000"
+read_timeout = "20s"
+write_timeout = "40s"
+
+[units]
+packs = ["imperial"]
+`)
+	env := map[string]string{
+		"GOVERTER_CONFIG":        path,
+		"GOVERTER_READ_TIMEOUT":  "25s",
+		"GOVERTER_WRITE_TIMEOUT": "45s",
+	}
+	cfg, sources, err := loadConfig([]string{"-write-timeout", "50s"}, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		got    any
+		want   any
+		source string
+	}{
+		{"server.idle_timeout", cfg.IdleTimeout, 2 * time.Minute, "default"},
+		{"server.addr", cfg.Addr, ":7000", "file"},
+		{"units.packs", cfg.UnitPacks, []string{"imperial"}, "file"},
+		{"server.read_timeout", cfg.ReadTimeout, 25 * time.Second, "env"},
+		{"server.write_timeout", cfg.WriteTimeout, 50 * time.Second, "flag"},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.key, tt.got, tt.want)
+		}
+		if sources[tt.key] != tt.source {
+			t.Errorf("%s came from %s, want %s", tt.key, sources[tt.key], tt.source)
+		}
+	}
+}
+
+func TestLoadConfigLists(t *testing.T) {
+	path := writeConfigFile(t, `
+[units]
+files = ["lab, shared.json", "extra.json"]
+
+[format]
+precision = ["angle=6", "data_storage=2"]
+`)
+	cfg, _, err := loadConfig([]string{"-config", path}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if want := []string{"lab, shared.json", "extra.json"}; !reflect.DeepEqual(cfg.UnitFiles, want) {
+		t.Errorf("UnitFiles = %q, want %q", cfg.UnitFiles, want)
+	}
+	if want := map[string]int{"angle": 6, "data_storage": 2}; !reflect.DeepEqual(cfg.Precision, want) {
+		t.Errorf("Precision = %v, want %v", cfg.Precision, want)
+	}
+
+	// A table gives the same items
+	path = writeConfigFile(t, "[format.precision]\nangle = 6\ndata_storage = 2\n")
+	cfg, _, err = loadConfig([]string{"-config", path}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if want := map[string]int{"angle": 6, "data_storage": 2}; !reflect.DeepEqual(cfg.Precision, want) {
+		t.Errorf("Precision from a table = %v, want %v", cfg.Precision, want)
+	}
+
+	// Variables and flags have no array syntax and split on commas
+	env := map[string]string{"GOVERTER_UNIT_FILES": "a.json, b.json"}
+	cfg, _, err = loadConfig(nil, func(key string) string { return env[key] })
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if want := []string{"a.json", "b.json"}; !reflect.DeepEqual(cfg.UnitFiles, want) {
+		t.Errorf("UnitFiles = %q, want %q", cfg.UnitFiles, want)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"unknown key", "[server]\nport = 80\n", "unknown settings: server.port"},
+		{"array for a single value", "[server]\naddr = [\":1\", \":2\"]\n", "server.addr: expected a single value, not an array"},
+		{"invalid value", "[server]\nread_timeout = \"soon\"\n", `server.read_timeout: invalid duration "soon"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file)
+			_, _, err := loadConfig([]string{"-config", path}, func(string) string { return "" })
+			if err == nil || !strings.HasSuffix(err.Error(), tt.want) {
+				t.Errorf("loadConfig error = %v, want suffix %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintConfigRoundTrip(t *testing.T) {
+	path := writeConfigFile(t, `
+[admin]
+token = "s3cret"
+
+[units]
+files = ["lab, shared.json", "extra.json"]
+`)
+	cfg, sources, err := loadConfig([]string{"-config", path}, func(string) string { return "" })
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	var out strings.Builder
+	printConfig(&out, cfg, sources)
+	if strings.Contains(out.String(), "s3cret") {
+		t.Errorf("printConfig leaked a secret:\n%s", out.String())
+	}
+
+	values, err := parseConfigFile([]byte(out.String()))
+	if err != nil {
+		t.Fatalf("parsing printConfig output: %v\n%s", err, out.String())
+	}
+	if got, want := values["units.files"].items, cfg.UnitFiles; !reflect.DeepEqual(got, want) {
+		t.Errorf("printed units.files = %q, want %q", got, want)
+	}
+}
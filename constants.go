@@ -0,0 +1,12 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// Handler for the constants endpoint
+func constantsHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, converter.Constants())
+}
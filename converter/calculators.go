@@ -0,0 +1,98 @@
+package converter
+
+import "fmt"
+
+// EnergyCost prices an amount of energy at a price per kilowatt-hour.
+func (uc *UnitConverter) EnergyCost(energy float64, unit string, pricePerKWh float64) (kWh, cost float64, err error) {
+	kWh, err = uc.Convert(energy, unit, "kWh")
+	if err != nil {
+		return 0, 0, err
+	}
+	return kWh, kWh * pricePerKWh, nil
+}
+
+// productRule says that multiplying quantities of two dimensions gives a
+// quantity of a third. scale converts the product of the two base units
+// to the product's base unit (mass is based on grams, mass flow on kg/s).
+type productRule struct {
+	dimension string
+	scale     float64
+}
+
+// dimensionProducts lists the products the calculators understand, keyed
+// by the dimensions of the two factors.
+var dimensionProducts = map[[2]string]productRule{
+	{"power", "time"}:           {"energy", 1},
+	{"speed", "time"}:           {"length", 1},
+	{"volumetric_flow", "time"}: {"volume", 1},
+	{"mass_flow", "time"}:       {"mass", 1000},
+	{"data_rate", "time"}:       {"data_storage", 1},
+}
+
+// Multiply multiplies two quantities and expresses the product in unit to,
+// e.g. 1500 W × 3 h in kWh. The factors may come in either order.
+func (uc *UnitConverter) Multiply(a float64, aUnit string, b float64, bUnit string, to string) (float64, error) {
+	reg := uc.snapshot()
+	refs := [3]UnitRef{reg.resolve(aUnit), reg.resolve(bUnit), reg.resolve(to)}
+	for i, symbol := range []string{aUnit, bUnit, to} {
+		if !refs[i].Valid() {
+			return 0, &UnknownUnitError{Symbol: symbol, Role: "input", Suggestions: uc.Suggest(symbol)}
+		}
+	}
+	ua, ub, ut := refs[0].Unit(), refs[1].Unit(), refs[2].Unit()
+
+	rule, ok := dimensionProducts[[2]string{ua.Dimension, ub.Dimension}]
+	if !ok {
+		rule, ok = dimensionProducts[[2]string{ub.Dimension, ua.Dimension}]
+	}
+	if !ok {
+		return 0, fmt.Errorf("cannot multiply %s by %s", uc.GetDimensionName(ua.Dimension), uc.GetDimensionName(ub.Dimension))
+	}
+	if ut.Dimension != rule.dimension {
+		return 0, &DimensionMismatchError{From: aUnit + "·" + bUnit, FromDimension: rule.dimension, To: to, ToDimension: ut.Dimension}
+	}
+
+	product := ua.ToBase(a) * ub.ToBase(b) * rule.scale
+	return roundResult(ut.FromBase(product)), nil
+}
+
+// decimalSizes are the byte units that prefixes=decimal reads as powers of
+// 1000 rather than 1024, the way drive makers and networks count.
+var decimalSizes = map[string]float64{"KB": 1e3, "MB": 1e6, "GB": 1e9, "TB": 1e12}
+
+// decimalRates are the binary data rates that prefixes=decimal reads as
+// 1000-based, in bytes per second.
+var decimalRates = map[string]float64{"KB/s": 1e3, "MB/s": 1e6, "GB/s": 1e9}
+
+// TransferTime returns how many seconds it takes to move size at rate. With
+// decimal set, KB, MB, GB, TB, KB/s, MB/s and GB/s are read as 1000-based;
+// IEC units (KiB, MiB...) are always 1024-based.
+func (uc *UnitConverter) TransferTime(size float64, sizeUnit string, rate float64, rateUnit string, decimal bool) (float64, error) {
+	sizeRef, rateRef := uc.Resolve(sizeUnit), uc.Resolve(rateUnit)
+	if !sizeRef.Valid() {
+		return 0, &UnknownUnitError{Symbol: sizeUnit, Role: "size", Suggestions: uc.Suggest(sizeUnit)}
+	}
+	if !rateRef.Valid() {
+		return 0, &UnknownUnitError{Symbol: rateUnit, Role: "rate", Suggestions: uc.Suggest(rateUnit)}
+	}
+	if sizeRef.Unit().Dimension != "data_storage" {
+		return 0, fmt.Errorf("size unit %s is not a data size", sizeUnit)
+	}
+	if rateRef.Unit().Dimension != "data_rate" {
+		return 0, fmt.Errorf("rate unit %s is not a data rate", rateUnit)
+	}
+	if rate <= 0 {
+		return 0, fmt.Errorf("rate must be positive")
+	}
+
+	bytes, bytesPerSecond := sizeRef.Unit().ToBase(size), rateRef.Unit().ToBase(rate)
+	if decimal {
+		if factor, ok := decimalSizes[sizeRef.Symbol()]; ok {
+			bytes = size * factor
+		}
+		if factor, ok := decimalRates[rateRef.Symbol()]; ok {
+			bytesPerSecond = rate * factor
+		}
+	}
+	return bytes / bytesPerSecond, nil
+}
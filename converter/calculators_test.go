@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTransferTime(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		size     float64
+		sizeUnit string
+		rate     float64
+		rateUnit string
+		decimal  bool
+		want     float64 // Seconds
+	}{
+		{4.7, "GB", 50, "Mbit/s", true, 752},
+		{4.7, "GB", 50, "Mbit/s", false, 4.7 * (1 << 30) * 8 / 50e6},
+		{4.7, "GiB", 50, "Mbps", true, 4.7 * (1 << 30) * 8 / 50e6},
+		// Capitalised sizes and rates follow the same convention
+		{1, "GB", 1, "GB/s", false, 1},
+		{1, "GB", 1, "GB/s", true, 1},
+		{1, "MB", 1, "MB/s", false, 1},
+		{1, "TB", 1, "GB/s", true, 1000},
+		{1, "TB", 1, "GB/s", false, 1024},
+		{1, "GB", 1, "KB/s", true, 1e6},
+		{1, "GiB", 1, "MB/s", true, (1 << 30) / 1e6},
+	}
+	for _, tt := range tests {
+		got, err := uc.TransferTime(tt.size, tt.sizeUnit, tt.rate, tt.rateUnit, tt.decimal)
+		if err != nil {
+			t.Errorf("%g %s @ %g %s: %v", tt.size, tt.sizeUnit, tt.rate, tt.rateUnit, err)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9*tt.want {
+			t.Errorf("%g %s @ %g %s (decimal %v) = %v s, want %v s", tt.size, tt.sizeUnit, tt.rate, tt.rateUnit, tt.decimal, got, tt.want)
+		}
+	}
+}
+
+func TestDataRateAliases(t *testing.T) {
+	uc := NewUnitConverter()
+	for alias, want := range map[string]string{"Mbps": "Mbit/s", "Gbps": "Gbit/s", "MBps": "MB/s"} {
+		if got := uc.Resolve(alias).Symbol(); got != want {
+			t.Errorf("%s resolves to %q, want %q", alias, got, want)
+		}
+	}
+	// A lowercase m is milli, so mbps is not a data rate
+	for _, symbol := range []string{"mbps", "gbps"} {
+		if uc.Resolve(symbol).Valid() {
+			t.Errorf("%s resolves to %s", symbol, uc.Resolve(symbol).Symbol())
+		}
+	}
+}
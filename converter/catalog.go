@@ -0,0 +1,34 @@
+package converter
+
+// catalogVersion is the version of the catalog format written by exports.
+const catalogVersion = 1
+
+// ExportCatalog returns the current registry in the catalog format. Units
+// with custom conversions are compiled in and can't be expressed in the
+// format, so they are left out.
+func (uc *UnitConverter) ExportCatalog() UnitFile {
+	reg := uc.snapshot()
+	catalog := UnitFile{
+		Version:    catalogVersion,
+		Units:      make(map[string]Unit, len(reg.units)),
+		Aliases:    make(map[string]string, len(reg.aliases)),
+		Dimensions: make(map[string]string),
+		BaseUnits:  make(map[string]string),
+	}
+	for symbol, unit := range reg.units {
+		if unit.Conversion != nil {
+			continue
+		}
+		catalog.Units[symbol] = unit
+		catalog.Dimensions[unit.Dimension] = uc.GetDimensionName(unit.Dimension)
+		if base, ok := reg.baseUnit(unit.Dimension); ok {
+			catalog.BaseUnits[unit.Dimension] = base
+		}
+	}
+	for alias, symbol := range reg.aliases {
+		if _, ok := catalog.Units[symbol]; ok {
+			catalog.Aliases[alias] = symbol
+		}
+	}
+	return catalog
+}
@@ -0,0 +1,72 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Constant is a physical constant that can be used in expressions, as in
+// "0.5c to km/h".
+type Constant struct {
+	Symbol string  `json:"symbol"`
+	Key    string  `json:"key"` // always usable in expressions, even when the symbol is a unit
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	Exact  bool    `json:"exact"` // fixed by the SI definitions
+	Source string  `json:"source"`
+}
+
+// constants lists the known constants, using the 2019 SI defining values
+// and CODATA 2018.
+var constants = []Constant{
+	{Symbol: "c", Key: "speed_of_light", Name: "Speed of light in vacuum", Value: 299792458, Unit: "m/s", Exact: true, Source: sourceSI},
+	{Symbol: "h", Key: "planck", Name: "Planck constant", Value: 6.62607015e-34, Unit: "J·s", Exact: true, Source: sourceSI},
+	{Symbol: "N_A", Key: "avogadro", Name: "Avogadro constant", Value: 6.02214076e23, Unit: "mol⁻¹", Exact: true, Source: sourceSI},
+	{Symbol: "g_n", Key: "standard_gravity", Name: "Standard acceleration of gravity", Value: StandardGravity, Unit: "m/s²", Exact: true, Source: sourceNIST},
+	{Symbol: "R", Key: "gas_constant", Name: "Molar gas constant", Value: 8.314462618, Unit: "J/(mol·K)", Exact: true, Source: sourceSI},
+}
+
+// Constants returns the known constants.
+func Constants() []Constant {
+	return append([]Constant(nil), constants...)
+}
+
+// LookupConstant finds a constant by key, or by symbol when that isn't also
+// a unit symbol (hours take precedence over the Planck constant's "h").
+func (uc *UnitConverter) LookupConstant(name string) (Constant, bool) {
+	name = strings.TrimSpace(name)
+	for _, constant := range constants {
+		if strings.EqualFold(name, constant.Key) {
+			return constant, true
+		}
+	}
+	if uc.Resolve(name).Valid() {
+		return Constant{}, false
+	}
+	for _, constant := range constants {
+		if name == constant.Symbol {
+			return constant, true
+		}
+	}
+	return Constant{}, false
+}
+
+// ConvertConstant expresses value times a constant in unit to. Constants
+// whose unit isn't in the registry can only be given in that unit.
+func (uc *UnitConverter) ConvertConstant(value float64, constant Constant, to string) (float64, error) {
+	return uc.ConvertConstantContext(context.Background(), value, constant, to)
+}
+
+// ConvertConstantContext is ConvertConstant with a context; see
+// ConvertContext.
+func (uc *UnitConverter) ConvertConstantContext(ctx context.Context, value float64, constant Constant, to string) (float64, error) {
+	if !uc.Resolve(constant.Unit).Valid() {
+		if strings.TrimSpace(to) != constant.Unit {
+			return 0, fmt.Errorf("%s is in %s, which cannot be converted to other units", constant.Name, constant.Unit)
+		}
+		return value * constant.Value, nil
+	}
+	return uc.ConvertContext(ctx, value*constant.Value, constant.Unit, to)
+}
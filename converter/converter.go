@@ -0,0 +1,329 @@
+// Package converter holds goverter's unit registry and conversions: units
+// and the packs that provide them, unit definition files, and the
+// conversion, formatting and parsing functions built on them. The web
+// server, CLI and MCP server in the root package are one client of it;
+// other programs can import it to convert units or register their own
+// packs.
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Unit represents a unit with its conversion factor to the base unit and its dimension.
+type Unit struct {
+	Factor    float64 `json:"factor"`    // Factor to convert to the base unit
+	Dimension string  `json:"dimension"` // e.g., "mass" or "length"
+	Name      string  `json:"name"`      // Full name of the unit
+	// For temperature conversions, we need offset besides the factor
+	Offset float64 `json:"offset,omitempty"` // Used primarily for temperature conversions
+	// Units that aren't linear in the base unit provide their own mapping
+	Conversion Conversion `json:"-"`
+	UnitMetadata
+}
+
+// ToBase converts a value in this unit to the dimension's base unit.
+func (u Unit) ToBase(value float64) float64 {
+	if u.Conversion != nil {
+		return u.Conversion.ToBase(value)
+	}
+	return value*u.Factor + u.Offset
+}
+
+// FromBase converts a value in the dimension's base unit to this unit.
+func (u Unit) FromBase(value float64) float64 {
+	if u.Conversion != nil {
+		return u.Conversion.FromBase(value)
+	}
+	return (value - u.Offset) / u.Factor
+}
+
+// toBaseContext is ToBase for conversions that may block.
+func (u Unit) toBaseContext(ctx context.Context, value float64) (float64, error) {
+	if c, ok := u.Conversion.(ContextConversion); ok {
+		return c.ToBaseContext(ctx, value)
+	}
+	return u.ToBase(value), nil
+}
+
+// fromBaseContext is FromBase for conversions that may block.
+func (u Unit) fromBaseContext(ctx context.Context, value float64) (float64, error) {
+	if c, ok := u.Conversion.(ContextConversion); ok {
+		return c.FromBaseContext(ctx, value)
+	}
+	return u.FromBase(value), nil
+}
+
+// UnitConverter contains a mapping of unit symbols to their definitions.
+type UnitConverter struct {
+	mu        sync.RWMutex
+	updateMu  sync.Mutex // Serializes registry rebuilds
+	reg       *registry
+	unitFiles []string // Unit definition files layered over the built-in units
+	unitPacks []string // Optional unit packs enabled for this deployment
+	imported  UnitFile // Catalogs imported at runtime, layered over the unit files
+	listeners []func(RegistryDiff)
+	precision map[string]int // Configured decimal places per dimension
+
+	// A namespace's converter layers its own unit files and imports over
+	// its parent's registry
+	parent         *UnitConverter
+	namespace      string
+	namespaceFiles []string
+
+	referencePressure float64 // dB SPL reference in Pa, 0 for the default
+}
+
+// NewUnitConverter initializes the converter with all registered unit packs.
+// It panics if the packs conflict with each other.
+func NewUnitConverter() *UnitConverter {
+	reg, err := buildRegistry(nil, nil)
+	if err != nil {
+		panic("goverter: " + err.Error())
+	}
+	return &UnitConverter{reg: reg}
+}
+
+// builtinUnits returns the core unit definitions compiled into the binary.
+func builtinUnits() map[string]Unit {
+	return map[string]Unit{
+		// Mass units (base = gram)
+		"mg": {Factor: 0.001, Dimension: "mass", Name: "Milligram"},
+		"g":  {Factor: 1, Dimension: "mass", Name: "Gram"},
+		"kg": {Factor: 1000, Dimension: "mass", Name: "Kilogram"},
+		"t":  {Factor: 1000000, Dimension: "mass", Name: "Tonne"},
+		"oz": {Factor: 28.3495, Dimension: "mass", Name: "Ounce"},
+		"lb": {Factor: 453.59237, Dimension: "mass", Name: "Pound"},
+
+		// Length units (base = meter)
+		"nm": {Factor: 0.000000001, Dimension: "length", Name: "Nanometer"},
+		"µm": {Factor: 0.000001, Dimension: "length", Name: "Micrometer"},
+		"mm": {Factor: 0.001, Dimension: "length", Name: "Millimeter"},
+		"cm": {Factor: 0.01, Dimension: "length", Name: "Centimeter"},
+		"m":  {Factor: 1, Dimension: "length", Name: "Meter"},
+		"km": {Factor: 1000, Dimension: "length", Name: "Kilometer"},
+		"in": {Factor: 0.0254, Dimension: "length", Name: "Inch"},
+		"ft": {Factor: 0.3048, Dimension: "length", Name: "Foot"},
+		"yd": {Factor: 0.9144, Dimension: "length", Name: "Yard"},
+		"mi": {Factor: 1609.344, Dimension: "length", Name: "Mile"},
+
+		// Temperature units (base = Kelvin)
+		// For temperature, we need both factor and offset
+		"C":  {Factor: 1, Offset: 273.15, Dimension: "temperature", Name: "Celsius"},
+		"F":  {Factor: 5.0 / 9.0, Offset: 255.372, Dimension: "temperature", Name: "Fahrenheit"},
+		"K":  {Factor: 1, Offset: 0, Dimension: "temperature", Name: "Kelvin"},
+		"Ra": {Factor: 5.0 / 9.0, Offset: 0, Dimension: "temperature", Name: "Rankine"},
+
+		// Time units (base = second)
+		"ns":   {Factor: 1e-9, Dimension: "time", Name: "Nanosecond"},
+		"µs":   {Factor: 1e-6, Dimension: "time", Name: "Microsecond"},
+		"ms":   {Factor: 1e-3, Dimension: "time", Name: "Millisecond"},
+		"s":    {Factor: 1, Dimension: "time", Name: "Second"},
+		"min":  {Factor: 60, Dimension: "time", Name: "Minute"},
+		"h":    {Factor: 3600, Dimension: "time", Name: "Hour"},
+		"day":  {Factor: 86400, Dimension: "time", Name: "Day"},
+		"week": {Factor: 604800, Dimension: "time", Name: "Week"},
+		"year": {Factor: 31536000, Dimension: "time", Name: "Year (365 days)"},
+
+		// Frequency units (base = hertz)
+		"Hz":  {Factor: 1, Dimension: "frequency", Name: "Hertz"},
+		"kHz": {Factor: 1000, Dimension: "frequency", Name: "Kilohertz"},
+		"MHz": {Factor: 1e6, Dimension: "frequency", Name: "Megahertz"},
+		"GHz": {Factor: 1e9, Dimension: "frequency", Name: "Gigahertz"},
+		"THz": {Factor: 1e12, Dimension: "frequency", Name: "Terahertz"},
+
+		// Speed units (base = meters per second)
+		"m/s":  {Factor: 1, Dimension: "speed", Name: "Meters per second"},
+		"km/h": {Factor: 0.277778, Dimension: "speed", Name: "Kilometers per hour"},
+		"ft/s": {Factor: 0.3048, Dimension: "speed", Name: "Feet per second"},
+		"mph":  {Factor: 0.44704, Dimension: "speed", Name: "Miles per hour"},
+		"knot": {Factor: 0.514444, Dimension: "speed", Name: "Knot"},
+		"mach": {Factor: 340.29, Dimension: "speed", Name: "Mach (at sea level)"},
+
+		// Volume units (base = cubic meter)
+		"m³":    {Factor: 1, Dimension: "volume", Name: "Cubic Meter"},
+		"L":     {Factor: 0.001, Dimension: "volume", Name: "Liter"},
+		"gal":   {Factor: 0.003785411784, Dimension: "volume", Name: "Gallon (US)"},
+		"fl_oz": {Factor: 0.0000295735295625, Dimension: "volume", Name: "Fluid Ounce (US)"},
+
+		// Area units (base = square meter)
+		"m²":   {Factor: 1, Dimension: "area", Name: "Square Meter"},
+		"acre": {Factor: 4046.8564224, Dimension: "area", Name: "Acre"},
+		"ha":   {Factor: 10000, Dimension: "area", Name: "Hectare"},
+
+		// Energy units (base = joule)
+		"J":    {Factor: 1, Dimension: "energy", Name: "Joule"},
+		"cal":  {Factor: 4.184, Dimension: "energy", Name: "Calorie"},
+		"kcal": {Factor: 4184, Dimension: "energy", Name: "Kilocalorie"},
+
+		// Power units (base = watt)
+		"W":  {Factor: 1, Dimension: "power", Name: "Watt"},
+		"HP": {Factor: 735.49875, Dimension: "power", Name: "Horsepower"},
+
+		// Force units (base = newton)
+		"N":   {Factor: 1, Dimension: "force", Name: "Newton"},
+		"lbf": {Factor: 4.4482216153, Dimension: "force", Name: "Pound-force"},
+		"kgf": {Factor: StandardGravity, Dimension: "force", Name: "Kilogram-force"},
+
+		// Pressure units (base = pascal)
+		"Pa":  {Factor: 1, Dimension: "pressure", Name: "Pascal"},
+		"atm": {Factor: 101325, Dimension: "pressure", Name: "Atmosphere"},
+		"bar": {Factor: 100000, Dimension: "pressure", Name: "Bar"},
+
+		// Data Storage units (base = byte)
+		"B":   {Factor: 1, Dimension: "data_storage", Name: "Byte"},
+		"bit": {Factor: 0.125, Dimension: "data_storage", Name: "Bit"},
+		"KB":  {Factor: 1024, Dimension: "data_storage", Name: "Kilobyte"},
+		"MB":  {Factor: 1048576, Dimension: "data_storage", Name: "Megabyte"},
+		"GB":  {Factor: 1073741824, Dimension: "data_storage", Name: "Gigabyte"},
+
+		// Angle units (base = radian)
+		"rad":    {Factor: 1, Dimension: "angle", Name: "Radian"},
+		"deg":    {Factor: math.Pi / 180, Dimension: "angle", Name: "Degree"},
+		"arcmin": {Factor: math.Pi / 10800, Dimension: "angle", Name: "Minute"},
+		"arcsec": {Factor: math.Pi / 648000, Dimension: "angle", Name: "Second"},
+	}
+}
+
+// builtinBaseUnits returns the base unit of each core dimension.
+func builtinBaseUnits() map[string]string {
+	return map[string]string{
+		"mass":         "g",
+		"length":       "m",
+		"temperature":  "K",
+		"time":         "s",
+		"frequency":    "Hz",
+		"speed":        "m/s",
+		"volume":       "m³",
+		"area":         "m²",
+		"energy":       "J",
+		"power":        "W",
+		"force":        "N",
+		"pressure":     "Pa",
+		"data_storage": "B",
+		"angle":        "rad",
+	}
+}
+
+// Convert performs the conversion from one unit to another.
+func (uc *UnitConverter) Convert(value float64, from, to string) (float64, error) {
+	return uc.ConvertContext(context.Background(), value, from, to)
+}
+
+// ConvertContext is Convert with a context for cancellation, deadlines and
+// trace propagation; see ConvertRefContext.
+func (uc *UnitConverter) ConvertContext(ctx context.Context, value float64, from, to string) (float64, error) {
+	reg := uc.snapshot()
+	fromRef := reg.resolve(from)
+	if !fromRef.Valid() {
+		return 0, &UnknownUnitError{Symbol: from, Role: "source", Suggestions: uc.Suggest(from)}
+	}
+	toRef := reg.resolve(to)
+	if !toRef.Valid() {
+		return 0, &UnknownUnitError{Symbol: to, Role: "target", Suggestions: uc.Suggest(to)}
+	}
+
+	result, err := uc.ConvertRefContext(ctx, value, fromRef, toRef)
+	if errors.Is(err, ErrDimensionMismatch) {
+		return 0, &DimensionMismatchError{
+			From: from, FromDimension: fromRef.Unit().Dimension,
+			To: to, ToDimension: toRef.Unit().Dimension,
+		}
+	}
+	return result, err
+}
+
+// FormatResult formats the conversion result appropriately based on its
+// magnitude, or with the precision configured for the unit's dimension
+func (uc *UnitConverter) FormatResult(result float64, unit string) string {
+	if places, ok := uc.Precision(unit); ok {
+		return WithUnit(fmt.Sprintf("%.*f", places, result), unit)
+	}
+
+	// Use scientific notation for very large or very small numbers
+	absResult := math.Abs(result)
+	if absResult < 0.001 || absResult > 1000000 {
+		return WithUnit(fmt.Sprintf("%.6e", result), unit)
+	}
+
+	// For "normal" sized numbers, use appropriate decimal places
+	return WithUnit(fmt.Sprintf("%.*f", decimalPlaces(absResult), result), unit)
+}
+
+// decimalPlaces returns how many decimals to show for a number of the
+// given magnitude
+func decimalPlaces(absResult float64) int {
+	switch {
+	case absResult >= 1000:
+		return 0
+	case absResult >= 100:
+		return 1
+	case absResult >= 10:
+		return 2
+	case absResult >= 1:
+		return 3
+	default:
+		// For values less than 1, use more decimal places
+		return 4
+	}
+}
+
+// GetUnitsByDimension returns all units of a specific dimension. The map
+// is shared with the registry index and must not be modified.
+func (uc *UnitConverter) GetUnitsByDimension(dimension string) map[string]Unit {
+	return uc.snapshot().byDimension[dimension]
+}
+
+// GetAllDimensions returns all available dimensions, sorted. The slice is
+// shared with the registry index and must not be modified.
+func (uc *UnitConverter) GetAllDimensions() []string {
+	return uc.snapshot().dimensions
+}
+
+// GetDimensionName returns a human-friendly name for a dimension
+func (uc *UnitConverter) GetDimensionName(dimension string) string {
+	if name, ok := uc.snapshot().dimensionLabels[dimension]; ok {
+		return name
+	}
+	return defaultDimensionName(dimension)
+}
+
+// defaultDimensionName returns the display name of the core dimensions
+func defaultDimensionName(dimension string) string {
+	switch dimension {
+	case "volume":
+		return "Volume"
+	case "area":
+		return "Area"
+	case "energy":
+		return "Energy"
+	case "power":
+		return "Power"
+	case "force":
+		return "Force"
+	case "pressure":
+		return "Pressure"
+	case "data_storage":
+		return "Data Storage"
+	case "angle":
+		return "Angle"
+	case "mass":
+		return "Mass"
+	case "length":
+		return "Length"
+	case "temperature":
+		return "Temperature"
+	case "time":
+		return "Time"
+	case "frequency":
+		return "Frequency"
+	case "speed":
+		return "Speed"
+	default:
+		return dimension
+	}
+}
@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// US customary cooking volumes in cubic meters.
+const (
+	usCup        = 0.0002365882365
+	usTablespoon = usCup / 16
+	usTeaspoon   = usTablespoon / 3
+)
+
+// cookingPack adds kitchen volume units. A stick of butter is half a cup.
+func cookingPack() UnitPack {
+	return UnitPack{
+		Name: "cooking",
+		Units: withMetadata(map[string]Unit{
+			"cup":   {Factor: usCup, Dimension: "volume", Name: "Cup (US)"},
+			"tbsp":  {Factor: usTablespoon, Dimension: "volume", Name: "Tablespoon (US)"},
+			"tsp":   {Factor: usTeaspoon, Dimension: "volume", Name: "Teaspoon (US)"},
+			"stick": {Factor: usCup / 2, Dimension: "volume", Name: "Stick of butter"},
+			"mL":    {Factor: 1e-6, Dimension: "volume", Name: "Milliliter"},
+		}, map[string]UnitMetadata{
+			"cup":   meta(SystemUSCustomary, sourceNIST, "US customary cup, 8 US fluid ounces (about 236.6 mL).", "Recipes", "Cup_(unit)"),
+			"tbsp":  meta(SystemUSCustomary, sourceNIST, "US tablespoon, 1/16 cup or 3 teaspoons (about 14.8 mL).", "Recipes, medicine doses", "Tablespoon"),
+			"tsp":   meta(SystemUSCustomary, sourceNIST, "US teaspoon, 1/3 tablespoon (about 4.93 mL).", "Recipes, medicine doses", "Teaspoon"),
+			"stick": meta(SystemUSCustomary, sourceNIST, "A US stick of butter: half a cup, 8 tablespoons, about 113 g.", "US baking recipes", "Butter#Size"),
+			"mL":    meta(SystemNonSI, sourceSI, "One thousandth of a litre, one cubic centimetre.", "Recipes, medicine, drinks", "Litre"),
+		}),
+		Aliases: map[string]string{
+			"cups":       "cup",
+			"tablespoon": "tbsp", "tablespoons": "tbsp", "Tbsp": "tbsp",
+			"teaspoon": "tsp", "teaspoons": "tsp",
+			"sticks": "stick",
+			"ml":     "mL", "cc": "mL",
+			"fl oz": "fl_oz", "floz": "fl_oz",
+		},
+	}
+}
+
+func init() {
+	RegisterProvider(cookingPack())
+}
+
+// ingredientDensities holds approximate densities of common ingredients as
+// measured in a kitchen (spooned and leveled for dry goods), in g/mL.
+var ingredientDensities = map[string]float64{
+	"water":          1.0,
+	"milk":           1.03,
+	"flour":          0.528, // 125 g per cup, all-purpose
+	"sugar":          0.845, // 200 g per cup, granulated
+	"brown sugar":    0.93,  // 220 g per cup, packed
+	"powdered sugar": 0.507, // 120 g per cup
+	"butter":         0.959, // 227 g per cup, 113 g per stick
+	"oil":            0.92,
+	"honey":          1.42,
+	"salt":           1.2,   // Table salt
+	"rice":           0.85,  // Uncooked long grain
+	"cocoa":          0.359, // 85 g per cup
+}
+
+// ingredientAliases maps other names to ingredientDensities keys.
+var ingredientAliases = map[string]string{
+	"all-purpose flour": "flour", "ap flour": "flour", "plain flour": "flour",
+	"granulated sugar": "sugar", "white sugar": "sugar", "caster sugar": "sugar",
+	"icing sugar": "powdered sugar", "confectioners sugar": "powdered sugar",
+	"vegetable oil": "oil", "olive oil": "oil",
+	"cocoa powder": "cocoa",
+}
+
+// Ingredients returns the known ingredient names, sorted.
+func Ingredients() []string {
+	names := make([]string, 0, len(ingredientDensities))
+	for name := range ingredientDensities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupIngredient returns the canonical name and density (g/mL) of an
+// ingredient.
+func LookupIngredient(name string) (string, float64, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if canonical, ok := ingredientAliases[name]; ok {
+		name = canonical
+	}
+	density, ok := ingredientDensities[name]
+	if !ok {
+		return "", 0, fmt.Errorf("unknown ingredient %q (known: %s)", name, strings.Join(Ingredients(), ", "))
+	}
+	return name, density, nil
+}
+
+// ConvertWithIngredient converts like Convert, but also bridges volume and
+// mass units through the density of an ingredient. bridged reports whether
+// the density was used.
+func (uc *UnitConverter) ConvertWithIngredient(ctx context.Context, value float64, from, to string, density float64) (result float64, bridged bool, err error) {
+	result, err = uc.ConvertContext(ctx, value, from, to)
+	var mismatch *DimensionMismatchError
+	if !errors.As(err, &mismatch) {
+		return result, false, err
+	}
+
+	fromUnit, toUnit := uc.Resolve(from).Unit(), uc.Resolve(to).Unit()
+	switch {
+	case !IsVolumeMass(fromUnit.Dimension, toUnit.Dimension):
+		return 0, false, err
+	case fromUnit.Dimension == "volume":
+		// Volume is based on cubic meters, mass on grams
+		milliliters := fromUnit.ToBase(value) * 1e6
+		return roundResult(toUnit.FromBase(milliliters * density)), true, nil
+	default:
+		milliliters := fromUnit.ToBase(value) / density
+		return roundResult(toUnit.FromBase(milliliters / 1e6)), true, nil
+	}
+}
+
+// IsVolumeMass reports whether two dimensions are volume and mass, in
+// either order.
+func IsVolumeMass(a, b string) bool {
+	return (a == "volume" && b == "mass") || (a == "mass" && b == "volume")
+}
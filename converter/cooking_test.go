@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvertWithIngredient(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		value      float64
+		from, to   string
+		ingredient string
+		want       float64
+		bridged    bool
+	}{
+		{1, "cup", "g", "flour", 124.918588872, true},
+		{1, "stick", "g", "butter", 113.444059402, true},
+		{200, "g", "cup", "sugar", 1.000414873, true},
+		{1, "kg", "L", "water", 1, true},
+		{1, "cup", "tbsp", "flour", 16, false}, // Same dimension, density ignored
+	}
+	for _, tt := range tests {
+		_, density, err := LookupIngredient(tt.ingredient)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, bridged, err := uc.ConvertWithIngredient(context.Background(), tt.value, tt.from, tt.to, density)
+		if err != nil || math.Abs(got-tt.want) > 1e-6 || bridged != tt.bridged {
+			t.Errorf("ConvertWithIngredient(%v, %s, %s, %s) = %v, %v, %v, want %v, %v", tt.value, tt.from, tt.to, tt.ingredient, got, bridged, err, tt.want, tt.bridged)
+		}
+	}
+
+	if _, _, err := uc.ConvertWithIngredient(context.Background(), 1, "cup", "m", 1); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ConvertWithIngredient(1, cup, m) error = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestLookupIngredient(t *testing.T) {
+	tests := []struct {
+		name, want string
+		density    float64
+	}{
+		{"flour", "flour", 0.528},
+		{" Plain Flour ", "flour", 0.528},
+		{"icing sugar", "powdered sugar", 0.507},
+	}
+	for _, tt := range tests {
+		name, density, err := LookupIngredient(tt.name)
+		if err != nil || name != tt.want || density != tt.density {
+			t.Errorf("LookupIngredient(%q) = %q, %v, %v, want %q, %v", tt.name, name, density, err, tt.want, tt.density)
+		}
+	}
+	if _, _, err := LookupIngredient("sawdust"); err == nil {
+		t.Error("LookupIngredient(sawdust) succeeded, want an error")
+	}
+}
@@ -0,0 +1,57 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Expression is a parsed conversion request such as "5 kg to lb".
+type Expression struct {
+	Value float64 `json:"value"`
+	From  string  `json:"from"`
+	To    string  `json:"to"`
+}
+
+// expressionPattern matches "<number> <unit> <connector> <unit>". The space
+// between the number and the first unit is optional ("5kg to lb"). Numbers
+// may be mixed numbers ("2 3/4"), fractions ("1/2", "½"), or decimals with
+// thousands separators other than plain spaces ("1,234.5"); ParseNumber
+// reads them.
+var expressionPattern = regexp.MustCompile(
+	`^\s*([-+−]?(?:\d+\s+\d+/\d+|\d+/\d+|(?:\d+\s*)?[½⅓⅔¼¾⅕⅖⅗⅘⅙⅚⅛⅜⅝⅞]|(?:\d[\d.,'’\x{00A0}\x{202F}]*\d|\d|[.,]\d+)(?:[eE][-+]?\d+)?))\s*(.+?)\s+(?:to|in|as|->|=>)\s+(.+?)\s*$`)
+
+// ParseExpression parses a free-form conversion like "5 kg to lb",
+// "100 F in C" or "3.5e3 m -> km". Units are returned as written.
+func ParseExpression(expr string) (Expression, error) {
+	m := expressionPattern.FindStringSubmatch(expr)
+	if m == nil {
+		return Expression{}, fmt.Errorf("cannot parse %q: expected something like \"5 kg to lb\"", strings.TrimSpace(expr))
+	}
+	value, err := ParseNumber(m[1], false)
+	if err != nil {
+		return Expression{}, fmt.Errorf("invalid value: %s", m[1])
+	}
+	return Expression{Value: value, From: m[2], To: m[3]}, nil
+}
+
+// Evaluate parses and performs a conversion expression. The source may be
+// a constant instead of a unit ("0.5c to km/h").
+func (uc *UnitConverter) Evaluate(expr string) (Expression, float64, error) {
+	return uc.EvaluateContext(context.Background(), expr)
+}
+
+// EvaluateContext is Evaluate with a context; see ConvertContext.
+func (uc *UnitConverter) EvaluateContext(ctx context.Context, expr string) (Expression, float64, error) {
+	e, err := ParseExpression(expr)
+	if err != nil {
+		return Expression{}, 0, err
+	}
+	if constant, ok := uc.LookupConstant(e.From); ok {
+		result, err := uc.ConvertConstantContext(ctx, e.Value, constant, e.To)
+		return e, result, err
+	}
+	result, err := uc.ConvertContext(ctx, e.Value, e.From, e.To)
+	return e, result, err
+}
@@ -0,0 +1,164 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Notation selects how a conversion result is rendered.
+type Notation string
+
+// Supported output notations.
+const (
+	NotationAuto        Notation = "auto"        // FormatResult's magnitude-based formatting
+	NotationEngineering Notation = "engineering" // Exponent is a multiple of 3, e.g. 12.00e-6 m
+	NotationSI          Notation = "si"          // Automatic SI prefix, e.g. 12.00 µm
+)
+
+// MaxPrecision is the largest number of decimal places that can be
+// configured or requested.
+const MaxPrecision = 15
+
+// SetPrecision sets the decimal places FormatResult uses for the given
+// dimensions instead of its magnitude-based heuristic.
+func (uc *UnitConverter) SetPrecision(precision map[string]int) {
+	copied := make(map[string]int, len(precision))
+	for dimension, places := range precision {
+		copied[dimension] = places
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.precision = copied
+}
+
+// Precision returns the configured decimal places for a unit's dimension.
+func (uc *UnitConverter) Precision(unit string) (int, bool) {
+	uc.mu.RLock()
+	reg, precision := uc.reg, uc.precision
+	uc.mu.RUnlock()
+	_, u, ok := reg.lookup(unit)
+	if !ok {
+		return 0, false
+	}
+	places, ok := precision[u.Dimension]
+	return places, ok
+}
+
+// ParsePrecision validates a requested number of decimal places.
+func ParsePrecision(value string) (int, error) {
+	places, err := strconv.Atoi(value)
+	if err != nil || places < 0 || places > MaxPrecision {
+		return 0, fmt.Errorf("invalid precision %q: must be a whole number between 0 and %d", value, MaxPrecision)
+	}
+	return places, nil
+}
+
+// ParseNotation validates a notation name. An empty name means NotationAuto.
+func ParseNotation(name string) (Notation, error) {
+	switch n := Notation(strings.ToLower(strings.TrimSpace(name))); n {
+	case "":
+		return NotationAuto, nil
+	case NotationAuto, NotationEngineering, NotationSI:
+		return n, nil
+	default:
+		return "", fmt.Errorf("unknown notation %q (use auto, engineering or si)", name)
+	}
+}
+
+// siPrefixes maps powers of ten that are multiples of 3 to their SI prefix.
+// Centi, deci, deca and hecto are left out on purpose: they don't fit
+// engineering steps.
+var siPrefixes = map[int]string{
+	24: "Y", 21: "Z", 18: "E", 15: "P", 12: "T", 9: "G", 6: "M", 3: "k",
+	0: "", -3: "m", -6: "µ", -9: "n", -12: "p", -15: "f", -18: "a", -21: "z", -24: "y",
+}
+
+// prefixableUnits are the unprefixed symbols that SI prefixes may be
+// attached to. Data units are excluded because KB, MB and GB are binary
+// multiples here.
+var prefixableUnits = map[string]bool{
+	"m": true, "g": true, "s": true, "L": true, "Hz": true,
+	"J": true, "W": true, "N": true, "Pa": true, "T": true,
+}
+
+// splitSIPrefix splits a unit symbol into its SI prefix exponent and the
+// unprefixed symbol, e.g. "km" gives (3, "m"). ok is false when the unit
+// cannot carry an SI prefix.
+func splitSIPrefix(symbol string) (exponent int, base string, ok bool) {
+	if prefixableUnits[symbol] {
+		return 0, symbol, true
+	}
+	for exponent, prefix := range siPrefixes {
+		if prefix != "" && strings.HasPrefix(symbol, prefix) && prefixableUnits[symbol[len(prefix):]] {
+			return exponent, symbol[len(prefix):], true
+		}
+	}
+	return 0, "", false
+}
+
+// FormatResultAs formats a result in the given notation. Units that can't
+// take an SI prefix (feet, pounds, ...) fall back to engineering notation
+// in NotationSI.
+func (uc *UnitConverter) FormatResultAs(result float64, unit string, notation Notation) string {
+	if notation == NotationAuto || notation == "" {
+		return uc.FormatResult(result, unit)
+	}
+	if result == 0 || math.IsInf(result, 0) || math.IsNaN(result) {
+		return WithUnit(fmt.Sprintf("%g", result), unit)
+	}
+
+	symbol := unit
+	if canonical, _, ok := uc.snapshot().lookup(unit); ok {
+		symbol = canonical
+	}
+	if notation == NotationSI {
+		if exponent, base, ok := splitSIPrefix(symbol); ok {
+			mantissa, exponent := engineeringParts(result*math.Pow10(exponent), -24, 24)
+			return fmt.Sprintf("%.*f %s%s", decimalPlaces(math.Abs(mantissa)), mantissa, siPrefixes[exponent], base)
+		}
+	}
+
+	mantissa, exponent := engineeringParts(result, math.MinInt32, math.MaxInt32)
+	if exponent == 0 {
+		return WithUnit(fmt.Sprintf("%.*f", decimalPlaces(math.Abs(mantissa)), mantissa), unit)
+	}
+	return WithUnit(fmt.Sprintf("%.*fe%d", decimalPlaces(math.Abs(mantissa)), mantissa, exponent), unit)
+}
+
+// adjacentSymbols are written straight after the number, as in "12.5%".
+var adjacentSymbols = map[string]bool{"%": true, "‰": true}
+
+// WithUnit joins a formatted number and a unit symbol.
+func WithUnit(number, unit string) string {
+	if adjacentSymbols[unit] {
+		return number + unit
+	}
+	return number + " " + unit
+}
+
+// engineeringParts splits value into a mantissa in [1, 1000) and an
+// exponent that is a multiple of 3, clamped to [min, max]. The mantissa is
+// already rounded so that it never prints as 1000.
+func engineeringParts(value float64, min, max int) (float64, int) {
+	exponent := int(math.Floor(math.Log10(math.Abs(value))/3)) * 3
+	if exponent < min {
+		exponent = min
+	}
+	if exponent > max {
+		exponent = max
+	}
+	mantissa := roundTo(value/math.Pow10(exponent), decimalPlaces(math.Abs(value/math.Pow10(exponent))))
+	if math.Abs(mantissa) >= 1000 && exponent+3 <= max {
+		exponent += 3
+		mantissa = roundTo(value/math.Pow10(exponent), decimalPlaces(math.Abs(value/math.Pow10(exponent))))
+	}
+	return mantissa, exponent
+}
+
+// roundTo rounds value to the given number of decimal places.
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow10(places)
+	return math.Round(value*scale) / scale
+}
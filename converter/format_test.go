@@ -0,0 +1,51 @@
+package converter
+
+import "testing"
+
+func TestFormatResultAs(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		result   float64
+		unit     string
+		notation Notation
+		want     string
+	}{
+		{0.000012, "m", NotationEngineering, "12.00e-6 m"},
+		{42, "m", NotationEngineering, "42.00 m"},
+		{1250, "ft", NotationEngineering, "1.250e3 ft"},
+		{999999, "m", NotationEngineering, "1.000e6 m"}, // Rounds up to the next step
+		{0, "m", NotationEngineering, "0 m"},
+		{0.000012, "m", NotationSI, "12.00 µm"},
+		{1500, "km", NotationSI, "1.500 Mm"},
+		{2.5, "kg", NotationSI, "2.500 kg"},
+		{0.25, "kg", NotationSI, "250.0 g"},
+		{0.0047, "L", NotationSI, "4.700 mL"},
+		{1250, "ft", NotationSI, "1.250e3 ft"}, // No SI prefix for feet
+		{1250, "MB", NotationSI, "1.250e3 MB"}, // Nor for binary data units
+		{12.5, "m", NotationAuto, "12.50 m"},
+	}
+	for _, tt := range tests {
+		if got := uc.FormatResultAs(tt.result, tt.unit, tt.notation); got != tt.want {
+			t.Errorf("FormatResultAs(%v, %s, %s) = %q, want %q", tt.result, tt.unit, tt.notation, got, tt.want)
+		}
+	}
+}
+
+func TestParseNotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Notation
+		wantErr bool
+	}{
+		{"", NotationAuto, false},
+		{"SI", NotationSI, false},
+		{" engineering ", NotationEngineering, false},
+		{"scientific", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseNotation(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseNotation(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
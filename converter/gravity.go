@@ -0,0 +1,61 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// StandardGravity is standard acceleration of gravity, g₀, in m/s².
+const StandardGravity = 9.80665
+
+// gravityPresets are surface gravities that can be named instead of given
+// in m/s².
+var gravityPresets = map[string]float64{
+	"earth":   StandardGravity,
+	"moon":    1.625,
+	"mars":    3.72076,
+	"jupiter": 24.79,
+}
+
+// ParseGravity reads a gravity in m/s² or a preset name such as "moon".
+// An empty value means StandardGravity.
+func ParseGravity(value string) (float64, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return StandardGravity, nil
+	}
+	if g, ok := gravityPresets[value]; ok {
+		return g, nil
+	}
+	g, err := ParseNumber(value, false)
+	if err != nil || g <= 0 {
+		return 0, fmt.Errorf("invalid gravity %q: must be a positive number in m/s² or one of earth, moon, mars, jupiter", value)
+	}
+	return g, nil
+}
+
+// ConvertWithGravity converts like Convert, but also bridges mass and
+// force units through weight = mass × gravity. bridged reports whether the
+// result depends on gravity, so callers can flag it as contextual.
+func (uc *UnitConverter) ConvertWithGravity(ctx context.Context, value float64, from, to string, gravity float64) (result float64, bridged bool, err error) {
+	result, err = uc.ConvertContext(ctx, value, from, to)
+	var mismatch *DimensionMismatchError
+	if !errors.As(err, &mismatch) {
+		return result, false, err
+	}
+
+	fromUnit, toUnit := uc.Resolve(from).Unit(), uc.Resolve(to).Unit()
+	switch {
+	case fromUnit.Dimension == "mass" && toUnit.Dimension == "force":
+		// Mass is based on grams, force on newtons
+		kilograms := fromUnit.ToBase(value) / 1000
+		return roundResult(toUnit.FromBase(kilograms * gravity)), true, nil
+	case fromUnit.Dimension == "force" && toUnit.Dimension == "mass":
+		kilograms := fromUnit.ToBase(value) / gravity
+		return roundResult(toUnit.FromBase(kilograms * 1000)), true, nil
+	default:
+		return 0, false, err
+	}
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvertWithGravity(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		value    float64
+		from, to string
+		gravity  float64
+		want     float64
+		bridged  bool
+	}{
+		{1, "kg", "N", StandardGravity, 9.80665, true},
+		{10, "kg", "N", gravityPresets["moon"], 16.25, true},
+		{9.80665, "N", "kg", StandardGravity, 1, true},
+		{1, "kg", "kgf", StandardGravity, 1, true},
+		{1, "lb", "lbf", StandardGravity, 1, true},
+		{100, "N", "g", 10, 10000, true},
+		{1, "kg", "lb", gravityPresets["moon"], 2.204622621849, false}, // Same dimension, gravity ignored
+	}
+	for _, tt := range tests {
+		got, bridged, err := uc.ConvertWithGravity(context.Background(), tt.value, tt.from, tt.to, tt.gravity)
+		if err != nil || math.Abs(got-tt.want) > 1e-9 || bridged != tt.bridged {
+			t.Errorf("ConvertWithGravity(%v, %s, %s, %v) = %v, %v, %v, want %v, %v", tt.value, tt.from, tt.to, tt.gravity, got, bridged, err, tt.want, tt.bridged)
+		}
+	}
+
+	if _, _, err := uc.ConvertWithGravity(context.Background(), 1, "m", "N", StandardGravity); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ConvertWithGravity(1, m, N) error = %v, want ErrDimensionMismatch", err)
+	}
+}
+
+func TestParseGravity(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{"", StandardGravity, false},
+		{"Moon", 1.625, false},
+		{" mars ", 3.72076, false},
+		{"3.5", 3.5, false},
+		{"0", 0, true},
+		{"-9.8", 0, true},
+		{"pluto", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseGravity(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseGravity(%q) = %v, %v, want %v (error %v)", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Errors returned by ConvertRef. Convert wraps them in descriptive errors
+// that still match with errors.Is.
+var (
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrDimensionMismatch = errors.New("cannot convert between different dimensions")
+	ErrOutOfRange        = errors.New("value is outside the range of the target unit")
+)
+
+// Is lets UnknownUnitError match ErrUnknownUnit.
+func (e *UnknownUnitError) Is(target error) bool {
+	return target == ErrUnknownUnit
+}
+
+// DimensionMismatchError reports a conversion between units of different dimensions.
+type DimensionMismatchError struct {
+	From, FromDimension string
+	To, ToDimension     string
+}
+
+func (e *DimensionMismatchError) Error() string {
+	return fmt.Sprintf("cannot convert between different dimensions: %s (%s) and %s (%s)",
+		e.From, e.FromDimension, e.To, e.ToDimension)
+}
+
+// Is lets DimensionMismatchError match ErrDimensionMismatch.
+func (e *DimensionMismatchError) Is(target error) bool {
+	return target == ErrDimensionMismatch
+}
+
+// roundingFactor rounds results to 12 decimal places to hide floating point noise.
+const roundingFactor = 1e12
+
+func roundResult(result float64) float64 {
+	return math.Round(result*roundingFactor) / roundingFactor
+}
+
+// resolvedUnit is a registry entry addressed by UnitRef.
+type resolvedUnit struct {
+	symbol string
+	unit   Unit
+	table  *dimensionTable // Shared by every unit of the same dimension
+	pos    int             // Position of the unit in table
+	linear bool            // No offset and no custom conversion
+}
+
+// dimensionTable holds the precomputed factors between the units of one
+// dimension: factors[i*size+j] converts unit i to unit j when both are linear.
+type dimensionTable struct {
+	size    int
+	factors []float64
+}
+
+// UnitRef is a resolved unit handle. Converting with refs skips the symbol
+// lookups and, between linear units, uses a precomputed factor. A ref keeps
+// the definitions it was resolved against; resolve again after a reload to
+// pick up changes.
+type UnitRef struct {
+	reg   *registry
+	index int
+}
+
+// Valid reports whether the ref points to a unit.
+func (r UnitRef) Valid() bool {
+	return r.reg != nil
+}
+
+// Symbol returns the canonical symbol of the unit, even if it was resolved by alias.
+func (r UnitRef) Symbol() string {
+	if r.reg == nil {
+		return ""
+	}
+	return r.reg.handles[r.index].symbol
+}
+
+// Unit returns the unit definition.
+func (r UnitRef) Unit() Unit {
+	if r.reg == nil {
+		return Unit{}
+	}
+	return r.reg.handles[r.index].unit
+}
+
+// buildHandles assigns every unit a handle and precomputes pair factors.
+func (r *registry) buildHandles() {
+	r.handles = make([]resolvedUnit, 0, len(r.units))
+	r.handleIndex = make(map[string]int, len(r.units)+len(r.aliases))
+	for _, dim := range r.dimensions {
+		units := r.byDimension[dim]
+		table := &dimensionTable{size: len(units), factors: make([]float64, len(units)*len(units))}
+
+		// Walk units in a stable order so handles are deterministic
+		first := len(r.handles)
+		for _, symbol := range sortedKeys(units) {
+			unit := units[symbol]
+			r.handleIndex[symbol] = len(r.handles)
+			r.handles = append(r.handles, resolvedUnit{
+				symbol: symbol,
+				unit:   unit,
+				table:  table,
+				pos:    len(r.handles) - first,
+				linear: unit.Offset == 0 && unit.Conversion == nil,
+			})
+		}
+
+		for i, a := range r.handles[first:] {
+			for j, b := range r.handles[first:] {
+				if a.linear && b.linear {
+					table.factors[i*table.size+j] = a.unit.Factor / b.unit.Factor
+				}
+			}
+		}
+	}
+	for alias, symbol := range r.aliases {
+		if index, ok := r.handleIndex[symbol]; ok {
+			r.handleIndex[alias] = index
+		}
+	}
+}
+
+// resolve returns a ref for a symbol or alias, or an invalid ref.
+func (r *registry) resolve(symbol string) UnitRef {
+	index, ok := r.handleIndex[symbol]
+	if !ok {
+		return UnitRef{}
+	}
+	return UnitRef{reg: r, index: index}
+}
+
+// Resolve looks up a unit by symbol or alias once, for callers that convert
+// with the same units repeatedly. Check the result with Valid.
+func (uc *UnitConverter) Resolve(symbol string) UnitRef {
+	return uc.snapshot().resolve(symbol)
+}
+
+// ConvertRef converts value between resolved units. It doesn't allocate and
+// returns ErrUnknownUnit for invalid refs, ErrDimensionMismatch for units
+// of different dimensions and ErrOutOfRange when a custom conversion has no
+// finite result, e.g. 0 Pa in decibels.
+func (uc *UnitConverter) ConvertRef(value float64, from, to UnitRef) (float64, error) {
+	return uc.ConvertRefContext(context.Background(), value, from, to)
+}
+
+// ConvertRefContext is ConvertRef with a context, which is checked before
+// converting and passed to units with a ContextConversion. It returns the
+// context's error once it is done.
+func (uc *UnitConverter) ConvertRefContext(ctx context.Context, value float64, from, to UnitRef) (float64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if !from.Valid() || !to.Valid() {
+		return 0, ErrUnknownUnit
+	}
+	if to.reg != from.reg {
+		// Refs resolved before and after a reload: convert within one registry
+		if to = from.reg.resolve(to.Symbol()); !to.Valid() {
+			return 0, ErrUnknownUnit
+		}
+	}
+
+	f, t := &from.reg.handles[from.index], &from.reg.handles[to.index]
+	if f.table != t.table {
+		return 0, ErrDimensionMismatch
+	}
+
+	var result float64
+	if f.linear && t.linear {
+		result = value * f.table.factors[f.pos*f.table.size+t.pos]
+	} else {
+		// Go through the base unit; offsets (temperature) and custom
+		// conversions are handled by the units
+		base, err := f.unit.toBaseContext(ctx, value)
+		if err != nil {
+			return 0, err
+		}
+		if result, err = t.unit.fromBaseContext(ctx, base); err != nil {
+			return 0, err
+		}
+		if (math.IsNaN(result) || math.IsInf(result, 0)) && !math.IsNaN(value) && !math.IsInf(value, 0) {
+			return 0, ErrOutOfRange
+		}
+	}
+	return roundResult(result), nil
+}
@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+)
+
+// throughBase converts the way every conversion went before the factor
+// tables: to the base unit and back, with the units' own offsets and
+// custom conversions.
+func throughBase(ctx context.Context, value float64, from, to Unit) (float64, error) {
+	base, err := from.toBaseContext(ctx, value)
+	if err != nil {
+		return 0, err
+	}
+	result, err := to.fromBaseContext(ctx, base)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrOutOfRange
+	}
+	return roundResult(result), nil
+}
+
+// sameResult compares results that may differ in the last bits, since a
+// precomputed factor rounds once where the base unit rounds twice.
+func sameResult(a, b float64) bool {
+	return a == b || math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
+
+func TestConvertRefMatchesConvert(t *testing.T) {
+	uc := NewUnitConverter()
+	uc.SetUnitPacks(OptionalPacks())
+	ctx := context.Background()
+	snapshot := uc.Snapshot()
+
+	for dim, units := range snapshot.UnitsByDimension() {
+		for fromSymbol, fromUnit := range units {
+			for toSymbol, toUnit := range units {
+				from, to := uc.Resolve(fromSymbol), uc.Resolve(toSymbol)
+				for _, value := range []float64{0, 1, -40, 123.456} {
+					want, wantErr := throughBase(ctx, value, fromUnit, toUnit)
+					got, err := uc.ConvertRefContext(ctx, value, from, to)
+					if !errors.Is(err, wantErr) || !sameResult(got, want) {
+						t.Errorf("%s: ConvertRef(%v, %s, %s) = %v, %v, want %v, %v", dim, value, fromSymbol, toSymbol, got, err, want, wantErr)
+					}
+					bySymbol, err := uc.ConvertContext(ctx, value, fromSymbol, toSymbol)
+					if !errors.Is(err, wantErr) || bySymbol != got {
+						t.Errorf("%s: Convert(%v, %s, %s) = %v, %v, want %v as ConvertRef", dim, value, fromSymbol, toSymbol, bySymbol, err, got)
+					}
+				}
+			}
+		}
+	}
+
+	// An alias converts exactly like the unit it names, in either position
+	for alias, symbol := range snapshot.Aliases() {
+		ref := uc.Resolve(alias)
+		if ref.Symbol() != symbol {
+			t.Errorf("Resolve(%q) = %q, want %q", alias, ref.Symbol(), symbol)
+			continue
+		}
+		base, _ := snapshot.BaseUnit(ref.Unit().Dimension)
+		want, wantErr := uc.ConvertContext(ctx, 1, symbol, base)
+		got, err := uc.ConvertRefContext(ctx, 1, ref, uc.Resolve(base))
+		if !errors.Is(err, wantErr) || got != want {
+			t.Errorf("ConvertRef(1, %s, %s) = %v, %v, want %v, %v", alias, base, got, err, want, wantErr)
+		}
+		want, wantErr = uc.ConvertContext(ctx, 1, base, symbol)
+		got, err = uc.ConvertRefContext(ctx, 1, uc.Resolve(base), ref)
+		if !errors.Is(err, wantErr) || got != want {
+			t.Errorf("ConvertRef(1, %s, %s) = %v, %v, want %v, %v", base, alias, got, err, want, wantErr)
+		}
+	}
+}
+
+func TestConvertRefKnownValues(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{0, "C", "K", 273.15},
+		{300, "K", "C", 26.85},
+		{100, "C", "Ra", 671.67},
+		{1, "km", "m", 1000},
+		{94, "dB SPL", "dB SPL", 94},
+	}
+	for _, tt := range tests {
+		got, err := uc.ConvertRef(tt.value, uc.Resolve(tt.from), uc.Resolve(tt.to))
+		if err != nil || !sameResult(got, tt.want) {
+			t.Errorf("ConvertRef(%v, %s, %s) = %v, %v, want %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := uc.ConvertRef(0, uc.Resolve("Pa"), uc.Resolve("dB SPL")); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("ConvertRef(0, Pa, dB SPL) error = %v, want ErrOutOfRange", err)
+	}
+	if _, err := uc.ConvertRef(1, uc.Resolve("m"), uc.Resolve("kg")); !errors.Is(err, ErrDimensionMismatch) {
+		t.Errorf("ConvertRef(1, m, kg) error = %v, want ErrDimensionMismatch", err)
+	}
+	if _, err := uc.ConvertRef(1, uc.Resolve("nope"), uc.Resolve("m")); !errors.Is(err, ErrUnknownUnit) {
+		t.Errorf("ConvertRef(1, nope, m) error = %v, want ErrUnknownUnit", err)
+	}
+}
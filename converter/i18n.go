@@ -0,0 +1,241 @@
+package converter
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// pluralForms holds the spelled-out name of a unit for each plural category
+// a locale distinguishes. English only needs "one" and "other".
+type pluralForms struct {
+	One   string
+	Other string
+}
+
+// locale is one language of the message catalog.
+type locale struct {
+	// category picks the plural form for a formatted number
+	category func(number string) string
+	// units spells out unit names by symbol
+	units map[string]pluralForms
+	// fallback derives names for units missing from the catalog, such as
+	// those loaded from unit files
+	fallback func(name string) pluralForms
+}
+
+// DefaultLocale is used when a request asks for no or an unknown language.
+const DefaultLocale = "en"
+
+// locales is the i18n catalog, keyed by base language tag.
+var locales = map[string]*locale{
+	"en": {
+		category: englishPluralCategory,
+		fallback: englishUnitName,
+		units: map[string]pluralForms{
+			"mg": {"milligram", "milligrams"},
+			"g":  {"gram", "grams"},
+			"kg": {"kilogram", "kilograms"},
+			"t":  {"tonne", "tonnes"},
+			"oz": {"ounce", "ounces"},
+			"lb": {"pound", "pounds"},
+
+			"nm": {"nanometer", "nanometers"},
+			"µm": {"micrometer", "micrometers"},
+			"mm": {"millimeter", "millimeters"},
+			"cm": {"centimeter", "centimeters"},
+			"m":  {"meter", "meters"},
+			"km": {"kilometer", "kilometers"},
+			"in": {"inch", "inches"},
+			"ft": {"foot", "feet"},
+			"yd": {"yard", "yards"},
+			"mi": {"mile", "miles"},
+
+			"C":  {"degree Celsius", "degrees Celsius"},
+			"F":  {"degree Fahrenheit", "degrees Fahrenheit"},
+			"K":  {"kelvin", "kelvins"},
+			"Ra": {"degree Rankine", "degrees Rankine"},
+
+			"ns":   {"nanosecond", "nanoseconds"},
+			"µs":   {"microsecond", "microseconds"},
+			"ms":   {"millisecond", "milliseconds"},
+			"s":    {"second", "seconds"},
+			"min":  {"minute", "minutes"},
+			"h":    {"hour", "hours"},
+			"day":  {"day", "days"},
+			"week": {"week", "weeks"},
+			"year": {"year", "years"},
+
+			"Hz":  {"hertz", "hertz"},
+			"kHz": {"kilohertz", "kilohertz"},
+			"MHz": {"megahertz", "megahertz"},
+			"GHz": {"gigahertz", "gigahertz"},
+			"THz": {"terahertz", "terahertz"},
+
+			"m/s":  {"meter per second", "meters per second"},
+			"km/h": {"kilometer per hour", "kilometers per hour"},
+			"ft/s": {"foot per second", "feet per second"},
+			"mph":  {"mile per hour", "miles per hour"},
+			"knot": {"knot", "knots"},
+			"mach": {"mach", "mach"},
+
+			"m³":    {"cubic meter", "cubic meters"},
+			"L":     {"liter", "liters"},
+			"gal":   {"gallon", "gallons"},
+			"fl_oz": {"fluid ounce", "fluid ounces"},
+
+			"m²":   {"square meter", "square meters"},
+			"acre": {"acre", "acres"},
+			"ha":   {"hectare", "hectares"},
+
+			"J":    {"joule", "joules"},
+			"cal":  {"calorie", "calories"},
+			"kcal": {"kilocalorie", "kilocalories"},
+
+			"W":  {"watt", "watts"},
+			"HP": {"horsepower", "horsepower"},
+
+			"N":   {"newton", "newtons"},
+			"lbf": {"pound-force", "pounds-force"},
+			"kgf": {"kilogram-force", "kilograms-force"},
+
+			// A plain ratio is read as a bare number
+			"ratio": {"", ""},
+			"%":     {"percent", "percent"},
+			"‰":     {"per mille", "per mille"},
+
+			"Pa":  {"pascal", "pascals"},
+			"atm": {"atmosphere", "atmospheres"},
+			"bar": {"bar", "bars"},
+
+			"B":   {"byte", "bytes"},
+			"bit": {"bit", "bits"},
+			"KB":  {"kilobyte", "kilobytes"},
+			"MB":  {"megabyte", "megabytes"},
+			"GB":  {"gigabyte", "gigabytes"},
+
+			"rad":    {"radian", "radians"},
+			"deg":    {"degree", "degrees"},
+			"arcmin": {"arcminute", "arcminutes"},
+			"arcsec": {"arcsecond", "arcseconds"},
+
+			// Pack units the fallback rule gets wrong: invariant plurals
+			// and compound names
+			"G":     {"gauss", "gauss"},
+			"kG":    {"kilogauss", "kilogauss"},
+			"mG":    {"milligauss", "milligauss"},
+			"lx":    {"lux", "lux"},
+			"st":    {"stone", "stone"}, // As a weight: "11 stone"
+			"stick": {"stick of butter", "sticks of butter"},
+			"gₙ":    {"standard gravity", "standard gravities"},
+			"shaku": {"shaku", "shaku"},
+			"sun":   {"sun", "sun"},
+			"ken":   {"ken", "ken"},
+			"ri":    {"ri", "ri"},
+			"tsubo": {"tsubo", "tsubo"},
+			"gō":    {"gō", "gō"},
+			"shō":   {"shō", "shō"},
+			"kan":   {"kan", "kan"},
+			"monme": {"monme", "monme"},
+		},
+	},
+}
+
+// lookupLocale returns the catalog for a language tag such as "en-GB" or
+// an Accept-Language header, falling back to the default locale.
+func lookupLocale(tag string) *locale {
+	for _, part := range strings.Split(tag, ",") {
+		part, _, _ = strings.Cut(part, ";")
+		base, _, _ := strings.Cut(strings.TrimSpace(part), "-")
+		if l, ok := locales[strings.ToLower(base)]; ok {
+			return l
+		}
+	}
+	return locales[DefaultLocale]
+}
+
+// englishPluralCategory implements the CLDR rule for English: "one" for
+// exactly 1 without visible decimals, "other" for everything else.
+func englishPluralCategory(number string) string {
+	if strings.TrimPrefix(number, "-") == "1" {
+		return "one"
+	}
+	return "other"
+}
+
+// englishUnitName derives names from a unit's display name, e.g. "Gallon
+// (UK)" becomes "gallon"/"gallons". Names the rule gets wrong, such as
+// invariant plurals, belong in the catalog.
+func englishUnitName(name string) pluralForms {
+	name, _, _ = strings.Cut(name, " (")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	// Pluralize the head noun of "x per y" and "x of y"
+	head, rest := name, ""
+	if i := headNounEnd(name); i >= 0 {
+		head, rest = name[:i], name[i:]
+	}
+	var plural string
+	switch {
+	case strings.HasSuffix(head, "foot"):
+		plural = strings.TrimSuffix(head, "foot") + "feet"
+	case strings.HasSuffix(head, "s"), strings.HasSuffix(head, "x"),
+		strings.HasSuffix(head, "ch"), strings.HasSuffix(head, "sh"):
+		plural = head + "es"
+	case strings.HasSuffix(head, "y") && len(head) > 1 && !strings.ContainsRune("aeiou", rune(head[len(head)-2])):
+		plural = strings.TrimSuffix(head, "y") + "ies"
+	default:
+		plural = head + "s"
+	}
+	return pluralForms{One: head + rest, Other: plural + rest}
+}
+
+// headNounEnd returns where the head noun of a unit name ends: before the
+// first " per " or " of ", or -1 when the whole name is the head.
+func headNounEnd(name string) int {
+	end := -1
+	for _, sep := range []string{" per ", " of "} {
+		if i := strings.Index(name, sep); i >= 0 && (end < 0 || i < end) {
+			end = i
+		}
+	}
+	return end
+}
+
+// spokenNumber formats a value compactly for reading aloud: at most six
+// significant digits and no exponent or trailing zeros.
+func spokenNumber(value float64) string {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 6, 64), 64)
+	if math.Abs(rounded) >= 1e21 {
+		return strconv.FormatFloat(rounded, 'g', 6, 64)
+	}
+	return strconv.FormatFloat(rounded, 'f', -1, 64)
+}
+
+// UnitName returns the spelled-out name of a unit for a value, e.g. "foot"
+// or "feet". Unknown units are returned unchanged.
+func (uc *UnitConverter) UnitName(unit string, number string, lang string) string {
+	l := lookupLocale(lang)
+	symbol, u, ok := uc.snapshot().lookup(unit)
+	if !ok {
+		return unit
+	}
+	forms, ok := l.units[symbol]
+	if !ok {
+		forms = l.fallback(u.Name)
+	}
+	if l.category(number) == "one" {
+		return forms.One
+	}
+	return forms.Other
+}
+
+// FormatResultName formats a result with the unit's full, correctly
+// pluralized name, e.g. "2.5 kilograms" or "1 foot".
+func (uc *UnitConverter) FormatResultName(result float64, unit string, lang string) string {
+	number := spokenNumber(result)
+	if name := uc.UnitName(unit, number, lang); name != "" {
+		return number + " " + name
+	}
+	return number
+}
@@ -0,0 +1,67 @@
+package converter
+
+import "testing"
+
+func TestUnitName(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		unit, number, lang string
+		want               string
+	}{
+		{"ft", "1", "en", "foot"},
+		{"ft", "2", "en", "feet"},
+		{"ft", "-1", "en", "foot"},
+		{"ft", "1.0", "en", "feet"}, // Visible decimals are plural
+		{"cups", "1", "en", "cup"},  // Aliases name their unit
+		{"C", "20", "en-GB", "degrees Celsius"},
+		{"lx", "300", "en", "lux"},
+		{"stick", "2", "", "sticks of butter"},
+		{"kg", "2", "xx", "kilograms"}, // Unknown languages use the default
+		{"parsec", "2", "en", "parsec"},
+	}
+	for _, tt := range tests {
+		if got := uc.UnitName(tt.unit, tt.number, tt.lang); got != tt.want {
+			t.Errorf("UnitName(%q, %q, %q) = %q, want %q", tt.unit, tt.number, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestFormatResultName(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		result float64
+		unit   string
+		want   string
+	}{
+		{1, "ft", "1 foot"},
+		{2.5, "kg", "2.5 kilograms"},
+		{1234567, "m", "1234570 meters"},
+		{3, "ratio", "3"},
+		{50, "%", "50 percent"},
+	}
+	for _, tt := range tests {
+		if got := uc.FormatResultName(tt.result, tt.unit, "en"); got != tt.want {
+			t.Errorf("FormatResultName(%v, %s) = %q, want %q", tt.result, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestEnglishUnitName(t *testing.T) {
+	tests := []struct {
+		name     string
+		one, two string
+	}{
+		{"Gallon (UK)", "gallon", "gallons"},
+		{"Square foot", "square foot", "square feet"},
+		{"Inch of mercury", "inch of mercury", "inches of mercury"},
+		{"Meter per second", "meter per second", "meters per second"},
+		{"Century", "century", "centuries"},
+		{"Day", "day", "days"},
+	}
+	for _, tt := range tests {
+		got := englishUnitName(tt.name)
+		if got.One != tt.one || got.Other != tt.two {
+			t.Errorf("englishUnitName(%q) = %q/%q, want %q/%q", tt.name, got.One, got.Other, tt.one, tt.two)
+		}
+	}
+}
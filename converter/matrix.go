@@ -0,0 +1,47 @@
+package converter
+
+import "context"
+
+// MatrixRow is one input value converted to every target unit.
+type MatrixRow struct {
+	Value   float64   `json:"value"`
+	Results []float64 `json:"results"` // In the order of the target units
+}
+
+// ConvertMatrix converts every value from one unit to each target unit.
+func (uc *UnitConverter) ConvertMatrix(ctx context.Context, values []float64, from string, to []string) ([]MatrixRow, error) {
+	reg := uc.snapshot()
+	fromRef := reg.resolve(from)
+	if !fromRef.Valid() {
+		return nil, &UnknownUnitError{Symbol: from, Role: "source", Suggestions: uc.Suggest(from)}
+	}
+	targets := make([]UnitRef, len(to))
+	for i, symbol := range to {
+		targets[i] = reg.resolve(symbol)
+		if !targets[i].Valid() {
+			return nil, &UnknownUnitError{Symbol: symbol, Role: "target", Suggestions: uc.Suggest(symbol)}
+		}
+		if targets[i].Unit().Dimension != fromRef.Unit().Dimension {
+			return nil, &DimensionMismatchError{
+				From: from, FromDimension: fromRef.Unit().Dimension,
+				To: symbol, ToDimension: targets[i].Unit().Dimension,
+			}
+		}
+	}
+
+	// One backing array for all results keeps allocations flat
+	cells := make([]float64, len(values)*len(targets))
+	rows := make([]MatrixRow, len(values))
+	for i, value := range values {
+		row := cells[i*len(targets) : (i+1)*len(targets)]
+		for j, target := range targets {
+			result, err := uc.ConvertRefContext(ctx, value, fromRef, target)
+			if err != nil {
+				return nil, err
+			}
+			row[j] = result
+		}
+		rows[i] = MatrixRow{Value: value, Results: row}
+	}
+	return rows, nil
+}
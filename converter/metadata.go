@@ -0,0 +1,149 @@
+package converter
+
+// UnitMetadata describes a unit for educational display.
+type UnitMetadata struct {
+	Description  string `json:"description,omitempty"`
+	Source       string `json:"source,omitempty"`     // Where the definition comes from, e.g. "SI Brochure"
+	System       string `json:"system,omitempty"`     // Measurement system, e.g. "SI", "Imperial", "US customary"
+	TypicalUse   string `json:"typicalUse,omitempty"` // Where the unit is commonly encountered
+	ReferenceURL string `json:"referenceUrl,omitempty"`
+}
+
+// Common measurement systems, the values of UnitMetadata.System.
+const (
+	SystemSI          = "SI"
+	SystemNonSI       = "Accepted for use with SI"
+	SystemMetric      = "Metric (non-SI)"
+	SystemImperial    = "Imperial / US customary"
+	SystemUSCustomary = "US customary"
+	SystemIEC         = "IEC / JEDEC"
+)
+
+// Common definition sources.
+const (
+	sourceSI       = "SI Brochure, 9th edition (BIPM, 2019)"
+	sourceYardPnd  = "International yard and pound agreement (1959)"
+	sourceNIST     = "NIST Special Publication 811"
+	sourceJEDEC    = "JEDEC JESD100B.01 (binary multiples)"
+	sourceISO80000 = "ISO 80000-3"
+)
+
+// meta builds UnitMetadata with a Wikipedia reference page.
+func meta(system, source, description, typicalUse, wikiPage string) UnitMetadata {
+	return UnitMetadata{
+		Description:  description,
+		Source:       source,
+		System:       system,
+		TypicalUse:   typicalUse,
+		ReferenceURL: "https://en.wikipedia.org/wiki/" + wikiPage,
+	}
+}
+
+// coreMetadata holds the metadata of the built-in units, keyed by symbol.
+var coreMetadata = map[string]UnitMetadata{
+	// Mass
+	"mg": meta(SystemSI, sourceSI, "One thousandth of a gram.", "Medication doses, dietary supplements", "Kilogram#Multiples_and_submultiples"),
+	"g":  meta(SystemSI, sourceSI, "One thousandth of a kilogram, the SI base unit of mass.", "Cooking, food labels, small objects", "Gram"),
+	"kg": meta(SystemSI, sourceSI, "SI base unit of mass, defined by fixing the Planck constant.", "Body weight, groceries, shipping", "Kilogram"),
+	"t":  meta(SystemNonSI, sourceSI, "Metric ton, equal to 1000 kilograms.", "Vehicles, cargo, industrial production", "Tonne"),
+	"oz": meta(SystemImperial, sourceYardPnd, "Avoirdupois ounce, one sixteenth of a pound.", "Food portions, postal weights", "Ounce"),
+	"lb": meta(SystemImperial, sourceYardPnd, "Avoirdupois pound, defined as exactly 0.45359237 kg.", "Body weight and groceries in the US and UK", "Pound_(mass)"),
+
+	// Length
+	"nm": meta(SystemSI, sourceSI, "One billionth of a metre.", "Wavelengths of light, semiconductor processes", "Nanometre"),
+	"µm": meta(SystemSI, sourceSI, "One millionth of a metre, also called a micron.", "Cell sizes, fibre diameters, machining tolerances", "Micrometre"),
+	"mm": meta(SystemSI, sourceSI, "One thousandth of a metre.", "Engineering drawings, rainfall, small parts", "Millimetre"),
+	"cm": meta(SystemSI, sourceSI, "One hundredth of a metre.", "Body height, everyday objects", "Centimetre"),
+	"m":  meta(SystemSI, sourceSI, "SI base unit of length: the distance light travels in vacuum in 1/299 792 458 of a second.", "Rooms, buildings, sports", "Metre"),
+	"km": meta(SystemSI, sourceSI, "One thousand metres.", "Road distances, running races", "Kilometre"),
+	"in": meta(SystemImperial, sourceYardPnd, "One twelfth of a foot, exactly 25.4 mm.", "Screen sizes, lumber, tools", "Inch"),
+	"ft": meta(SystemImperial, sourceYardPnd, "One third of a yard, exactly 0.3048 m.", "Body height, altitude in aviation", "Foot_(unit)"),
+	"yd": meta(SystemImperial, sourceYardPnd, "Exactly 0.9144 m.", "Sports fields, fabric", "Yard"),
+	"mi": meta(SystemImperial, sourceYardPnd, "Statute mile of 1760 yards.", "Road distances in the US and UK", "Mile"),
+
+	// Temperature
+	"C":  meta(SystemSI, sourceSI, "Degree Celsius: the kelvin scale shifted so that 0 °C is 273.15 K.", "Weather, cooking, medicine", "Celsius"),
+	"F":  meta(SystemUSCustomary, sourceNIST, "Degree Fahrenheit: water freezes at 32 °F and boils at 212 °F.", "Weather and cooking in the US", "Fahrenheit"),
+	"K":  meta(SystemSI, sourceSI, "SI base unit of thermodynamic temperature, starting at absolute zero.", "Science and engineering", "Kelvin"),
+	"Ra": meta(SystemUSCustomary, sourceNIST, "Absolute scale with Fahrenheit-sized degrees.", "Thermodynamics in US engineering", "Rankine_scale"),
+
+	// Time
+	"ns":   meta(SystemSI, sourceSI, "One billionth of a second.", "Computer timing, signal propagation", "Nanosecond"),
+	"µs":   meta(SystemSI, sourceSI, "One millionth of a second.", "Electronics, network latency", "Microsecond"),
+	"ms":   meta(SystemSI, sourceSI, "One thousandth of a second.", "Response times, audio latency", "Millisecond"),
+	"s":    meta(SystemSI, sourceSI, "SI base unit of time, defined by the caesium-133 hyperfine transition frequency.", "Everyday timing", "Second"),
+	"min":  meta(SystemNonSI, sourceSI, "60 seconds.", "Everyday timing", "Minute"),
+	"h":    meta(SystemNonSI, sourceSI, "60 minutes.", "Working hours, travel times", "Hour"),
+	"day":  meta(SystemNonSI, sourceSI, "24 hours.", "Calendars, schedules", "Day"),
+	"week": meta(SystemNonSI, sourceISO80000, "7 days.", "Calendars, schedules", "Week"),
+	"year": meta(SystemNonSI, sourceISO80000, "Common calendar year of 365 days.", "Ages, long durations", "Year"),
+
+	// Frequency
+	"Hz":  meta(SystemSI, sourceSI, "One cycle per second.", "Sound, mains electricity", "Hertz"),
+	"kHz": meta(SystemSI, sourceSI, "One thousand hertz.", "Audio, AM radio", "Hertz"),
+	"MHz": meta(SystemSI, sourceSI, "One million hertz.", "FM radio, microcontroller clocks", "Hertz"),
+	"GHz": meta(SystemSI, sourceSI, "One billion hertz.", "CPU clocks, Wi-Fi, cellular networks", "Hertz"),
+	"THz": meta(SystemSI, sourceSI, "One trillion hertz.", "Infrared and terahertz spectroscopy", "Terahertz_radiation"),
+
+	// Speed
+	"m/s":  meta(SystemSI, sourceSI, "SI derived unit of speed.", "Physics, wind speed", "Metre_per_second"),
+	"km/h": meta(SystemMetric, sourceSI, "Kilometres travelled in one hour.", "Road speed limits outside the US and UK", "Kilometres_per_hour"),
+	"ft/s": meta(SystemImperial, sourceNIST, "Feet travelled in one second.", "Ballistics, US engineering", "Foot_per_second"),
+	"mph":  meta(SystemImperial, sourceYardPnd, "Miles travelled in one hour.", "Road speed limits in the US and UK", "Miles_per_hour"),
+	"knot": meta(SystemNonSI, sourceNIST, "One nautical mile (1852 m) per hour.", "Aviation, shipping, wind speed", "Knot_(unit)"),
+	"mach": meta(SystemNonSI, "International Standard Atmosphere, sea level", "Speed of sound at sea level (about 340 m/s); the true value depends on air temperature.", "Aircraft speeds", "Mach_number"),
+
+	// Volume
+	"m³":    meta(SystemSI, sourceSI, "Volume of a cube with one-metre edges.", "Construction, water and gas metering", "Cubic_metre"),
+	"L":     meta(SystemNonSI, sourceSI, "One cubic decimetre.", "Drinks, fuel, containers", "Litre"),
+	"gal":   meta(SystemUSCustomary, sourceNIST, "US liquid gallon of 231 cubic inches.", "Fuel and milk in the US", "Gallon"),
+	"fl_oz": meta(SystemUSCustomary, sourceNIST, "US fluid ounce, one 128th of a US gallon.", "Beverages, recipes", "Fluid_ounce"),
+
+	// Area
+	"m²":   meta(SystemSI, sourceSI, "Area of a square with one-metre sides.", "Floor space, land plots", "Square_metre"),
+	"acre": meta(SystemImperial, sourceYardPnd, "43 560 square feet.", "Land and farm area in the US and UK", "Acre"),
+	"ha":   meta(SystemNonSI, sourceSI, "10 000 square metres.", "Agricultural and forest land", "Hectare"),
+
+	// Energy
+	"J":    meta(SystemSI, sourceSI, "SI derived unit of energy: one newton acting over one metre.", "Physics, engineering", "Joule"),
+	"cal":  meta(SystemNonSI, sourceNIST, "Thermochemical calorie, exactly 4.184 J.", "Chemistry", "Calorie"),
+	"kcal": meta(SystemNonSI, sourceNIST, "1000 thermochemical calories; the \"Calorie\" on food labels.", "Nutrition labels", "Calorie"),
+
+	// Power
+	"W":  meta(SystemSI, sourceSI, "SI derived unit of power: one joule per second.", "Appliances, light bulbs", "Watt"),
+	"HP": meta(SystemMetric, "DIN 66036", "Metric horsepower (PS), 75 kgf·m/s.", "Car and motor ratings", "Horsepower#Metric_horsepower"),
+
+	// Force
+	"N":   meta(SystemSI, sourceSI, "SI derived unit of force: accelerates one kilogram at one metre per second squared.", "Physics, engineering", "Newton_(unit)"),
+	"lbf": meta(SystemImperial, sourceNIST, "Force of one pound mass under standard gravity.", "US engineering, thrust", "Pound_(force)"),
+	"kgf": meta(SystemMetric, sourceNIST, "Force of one kilogram mass under standard gravity, 9.80665 N; also called kilopond.", "Older engineering data, scales, torque specifications", "Kilogram-force"),
+
+	// Pressure
+	"Pa":  meta(SystemSI, sourceSI, "SI derived unit of pressure: one newton per square metre.", "Science, weather (as hPa)", "Pascal_(unit)"),
+	"atm": meta(SystemNonSI, sourceNIST, "Standard atmosphere, exactly 101 325 Pa.", "Chemistry, diving", "Standard_atmosphere_(unit)"),
+	"bar": meta(SystemMetric, sourceSI, "Exactly 100 000 Pa, close to atmospheric pressure at sea level.", "Tyre pressure, weather, industry", "Bar_(unit)"),
+
+	// Data storage
+	"B":   meta(SystemIEC, "IEC 80000-13", "Eight bits.", "File sizes", "Byte"),
+	"bit": meta(SystemIEC, "IEC 80000-13", "The smallest unit of information, a binary digit.", "Network speeds, encryption key sizes", "Bit"),
+	"KB":  meta(SystemIEC, sourceJEDEC, "1024 bytes (binary kilobyte).", "Small files, memory pages", "Kilobyte"),
+	"MB":  meta(SystemIEC, sourceJEDEC, "1024 kilobytes (binary megabyte).", "Documents, photos", "Megabyte"),
+	"GB":  meta(SystemIEC, sourceJEDEC, "1024 megabytes (binary gigabyte).", "Memory sizes, videos", "Gigabyte"),
+
+	// Angle
+	"rad":    meta(SystemSI, sourceSI, "Angle subtended by an arc equal in length to the radius.", "Mathematics, physics", "Radian"),
+	"deg":    meta(SystemNonSI, sourceSI, "One 360th of a full turn.", "Navigation, geometry, everyday angles", "Degree_(angle)"),
+	"arcmin": meta(SystemNonSI, sourceSI, "One 60th of a degree.", "Astronomy, optics, firearm sights", "Minute_and_second_of_arc"),
+	"arcsec": meta(SystemNonSI, sourceSI, "One 60th of an arcminute.", "Astronomy, geodesy", "Minute_and_second_of_arc"),
+}
+
+// withMetadata returns units with their metadata filled in from metadata.
+func withMetadata(units map[string]Unit, metadata map[string]UnitMetadata) map[string]Unit {
+	for symbol, unit := range units {
+		if m, ok := metadata[symbol]; ok {
+			unit.UnitMetadata = m
+			units[symbol] = unit
+		}
+	}
+	return units
+}
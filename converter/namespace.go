@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+)
+
+var namespaceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// ValidateNamespaceName reports why name can't name a namespace, if it can't.
+func ValidateNamespaceName(name string) error {
+	if !namespaceNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid namespace name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// NewNamespace returns a converter for the named namespace. Its registry is
+// uc's, including catalogs imported into uc, with the namespace's unit files
+// and its own imports layered on top, so a namespace can define symbols that
+// conflict with the global ones without affecting them. It follows every
+// change to uc's registry.
+func (uc *UnitConverter) NewNamespace(name string, paths []string) (*UnitConverter, error) {
+	if err := ValidateNamespaceName(name); err != nil {
+		return nil, err
+	}
+	uc.mu.RLock()
+	ns := &UnitConverter{
+		parent:            uc,
+		namespace:         name,
+		namespaceFiles:    append([]string(nil), paths...),
+		precision:         uc.precision,
+		referencePressure: uc.referencePressure,
+	}
+	uc.mu.RUnlock()
+
+	reg, err := ns.build(UnitFile{})
+	if err != nil {
+		return nil, fmt.Errorf("namespace %s: %w", name, err)
+	}
+	ns.reg = reg
+	uc.OnChange(func(RegistryDiff) {
+		if _, err := ns.Reload(); err != nil {
+			log.Printf("Error rebuilding namespace %s: %v", name, err)
+		}
+	})
+	return ns, nil
+}
+
+// Namespace returns the name of the converter's namespace, or "" for the
+// global registry.
+func (uc *UnitConverter) Namespace() string {
+	return uc.namespace
+}
@@ -0,0 +1,238 @@
+package converter
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidNumber is returned by ParseNumber for input it can't read.
+var ErrInvalidNumber = errors.New("not a number")
+
+// decimalCommaLanguages write decimals with a comma and group thousands
+// with periods or spaces, e.g. "1.234,5".
+var decimalCommaLanguages = map[string]bool{
+	"bg": true, "ca": true, "cs": true, "da": true, "de": true, "el": true,
+	"es": true, "et": true, "fi": true, "fr": true, "hr": true, "hu": true,
+	"id": true, "it": true, "lt": true, "lv": true, "nb": true, "nl": true,
+	"nn": true, "no": true, "pl": true, "pt": true, "ro": true, "ru": true,
+	"sk": true, "sl": true, "sr": true, "sv": true, "tr": true, "uk": true,
+	"vi": true,
+}
+
+// decimalPointRegions use a decimal point despite their language, like
+// Swiss German ("1'234.5").
+var decimalPointRegions = map[string]bool{"de-ch": true, "de-li": true, "it-ch": true}
+
+// vulgarFractions are the Unicode fraction characters and their values.
+var vulgarFractions = map[rune]float64{
+	'½': 1.0 / 2, '⅓': 1.0 / 3, '⅔': 2.0 / 3, '¼': 1.0 / 4, '¾': 3.0 / 4,
+	'⅕': 1.0 / 5, '⅖': 2.0 / 5, '⅗': 3.0 / 5, '⅘': 4.0 / 5, '⅙': 1.0 / 6,
+	'⅚': 5.0 / 6, '⅛': 1.0 / 8, '⅜': 3.0 / 8, '⅝': 5.0 / 8, '⅞': 7.0 / 8,
+}
+
+// UsesDecimalComma reports whether the preferred language of a language
+// tag or Accept-Language header writes decimals with a comma.
+func UsesDecimalComma(tag string) bool {
+	first, _, _ := strings.Cut(tag, ",")
+	first, _, _ = strings.Cut(first, ";")
+	first = strings.ToLower(strings.TrimSpace(first))
+	if decimalPointRegions[first] {
+		return false
+	}
+	base, _, _ := strings.Cut(first, "-")
+	return decimalCommaLanguages[base]
+}
+
+// ParseNumber reads a number as people write it in documents:
+//   - plain or scientific notation: "1234.5", "-3.5e3"
+//   - thousands separators: "1,234.5", "1.234,5", "1 234,5", "1'234.5"
+//   - fractions and mixed numbers: "1/2", "2 3/4", "2¾"
+//
+// When a single comma or period could be either a decimal or a thousands
+// separator ("1,234"), decimalComma picks the reading: with it set "1,234"
+// is 1.234 and "1.234" is 1234. Separators that can only be one or the
+// other ("1,5", "1.234,5") are read the same either way.
+func ParseNumber(s string, decimalComma bool) (float64, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, "−", "-"))
+	negative := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		negative, s = true, strings.TrimSpace(rest)
+	} else {
+		s = strings.TrimSpace(strings.TrimPrefix(s, "+"))
+	}
+
+	value, err := parseUnsigned(s, decimalComma)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsInf(value, 0) {
+		return 0, ErrInvalidNumber
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// parseUnsigned parses a number without its sign.
+func parseUnsigned(s string, decimalComma bool) (float64, error) {
+	if s == "" {
+		return 0, ErrInvalidNumber
+	}
+
+	// "2¾" or "2 ¾"
+	if last, size := utf8.DecodeLastRuneInString(s); vulgarFractions[last] != 0 {
+		whole := strings.TrimSpace(s[:len(s)-size])
+		if whole == "" {
+			return vulgarFractions[last], nil
+		}
+		n, err := parseDigits(whole)
+		if err != nil {
+			return 0, err
+		}
+		return n + vulgarFractions[last], nil
+	}
+
+	// "3/4" or "2 3/4"
+	if strings.Contains(s, "/") {
+		whole, fraction := "", s
+		if i := strings.LastIndexByte(s, ' '); i >= 0 {
+			whole, fraction = strings.TrimSpace(s[:i]), s[i+1:]
+		}
+		numerator, denominator, _ := strings.Cut(fraction, "/")
+		n, err := parseDigits(numerator)
+		if err != nil {
+			return 0, err
+		}
+		d, err := parseDigits(denominator)
+		if err != nil || d == 0 {
+			return 0, ErrInvalidNumber
+		}
+		value := n / d
+		if whole != "" {
+			w, err := parseDigits(whole)
+			if err != nil {
+				return 0, err
+			}
+			value += w
+		}
+		return value, nil
+	}
+
+	mantissa, exponent := s, ""
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		mantissa, exponent = s[:i], s[i+1:]
+		if _, err := strconv.ParseInt(exponent, 10, 32); err != nil {
+			return 0, ErrInvalidNumber
+		}
+		exponent = "e" + exponent
+	}
+	normalized, err := normalizeDecimal(mantissa, decimalComma)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseFloat(normalized+exponent, 64)
+	if err != nil {
+		return 0, ErrInvalidNumber
+	}
+	return value, nil
+}
+
+// parseDigits parses a whole number made of ASCII digits only.
+func parseDigits(s string) (float64, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, ErrInvalidNumber
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// normalizeDecimal rewrites a decimal with any separators as digits with an
+// optional ".", validating the placement of thousands separators.
+func normalizeDecimal(s string, decimalComma bool) (string, error) {
+	// Spaces and apostrophes only ever group thousands
+	grouped := false
+	s = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', ' ', ' ', '\'', '’':
+			grouped = true
+			return '|'
+		}
+		return r
+	}, s)
+
+	commas, periods := strings.Count(s, ","), strings.Count(s, ".")
+	var decimal rune
+	switch {
+	case commas > 0 && periods > 0:
+		// Both: the last one is the decimal separator
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			decimal = ','
+		} else {
+			decimal = '.'
+		}
+	case commas == 1 && !grouped:
+		decimal = ambiguousSeparator(s, ',', decimalComma)
+	case periods == 1 && !grouped:
+		decimal = ambiguousSeparator(s, '.', !decimalComma)
+	case commas == 1:
+		decimal = ','
+	case periods == 1:
+		decimal = '.'
+	}
+
+	integer, fraction, _ := strings.Cut(s, string(decimal))
+	if decimal == 0 {
+		integer, fraction = s, ""
+	}
+	if strings.ContainsAny(fraction, ",.|") {
+		return "", ErrInvalidNumber
+	}
+	// Thousands separators must all be the same and split the integer part
+	// into groups of three
+	var separator rune
+	for _, r := range integer {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		if (r != ',' && r != '.' && r != '|') || (separator != 0 && r != separator) {
+			return "", ErrInvalidNumber
+		}
+		separator = r
+	}
+	digits := integer
+	if separator != 0 {
+		groups := strings.Split(integer, string(separator))
+		for i, group := range groups {
+			if (i == 0 && (len(group) < 1 || len(group) > 3)) || (i > 0 && len(group) != 3) {
+				return "", ErrInvalidNumber
+			}
+		}
+		digits = strings.Join(groups, "")
+	}
+
+	if digits == "" && fraction == "" {
+		return "", ErrInvalidNumber
+	}
+	if strings.Trim(digits, "0123456789") != "" || strings.Trim(fraction, "0123456789") != "" {
+		return "", ErrInvalidNumber
+	}
+	if fraction == "" {
+		return digits, nil
+	}
+	return digits + "." + fraction, nil
+}
+
+// ambiguousSeparator decides whether the only separator in s, which occurs
+// once, is the decimal separator. It groups thousands when followed by
+// exactly three digits after a non-zero integer part, unless preferDecimal
+// says this separator is the locale's decimal separator.
+func ambiguousSeparator(s string, separator rune, preferDecimal bool) rune {
+	integer, fraction, _ := strings.Cut(s, string(separator))
+	if preferDecimal || len(fraction) != 3 || integer == "" || integer == "0" || len(integer) > 3 {
+		return separator
+	}
+	return 0
+}
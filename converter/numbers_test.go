@@ -0,0 +1,102 @@
+package converter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUsesDecimalComma(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"", false},
+		{"en", false},
+		{"en-US", false},
+		{"de", true},
+		{"de-DE", true},
+		{"DE-at", true},
+		{"de-CH", false},
+		{"it-CH", false},
+		{"fr-CH,fr;q=0.9", true},
+		{"pt-BR", true},
+		{"en-GB,de;q=0.8", false},
+		{"de;q=0.9, en;q=0.8", true},
+	}
+	for _, tt := range tests {
+		if got := UsesDecimalComma(tt.tag); got != tt.want {
+			t.Errorf("UsesDecimalComma(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		locale string // Language tag deciding ambiguous separators
+		input  string
+		want   float64
+	}{
+		// Unambiguous input reads the same in every locale
+		{"en-US", "1234.5", 1234.5},
+		{"en-US", "-3.5e3", -3500},
+		{"en-US", "+42", 42},
+		{"en-US", "−12", -12},
+		{"en-US", "1,234.5", 1234.5},
+		{"de-DE", "1,234.5", 1234.5},
+		{"en-US", "1.234,5", 1234.5},
+		{"de-DE", "1.234,5", 1234.5},
+		{"en-US", "1,5", 1.5},
+		{"de-DE", "1,5", 1.5},
+		{"en-US", "0,500", 0.5},
+		{"en-US", "1,234,567.89", 1234567.89},
+		{"de-DE", "1.234.567,89", 1234567.89},
+
+		// A single separator followed by three digits follows the locale
+		{"en-US", "1,234", 1234},
+		{"en-US", "1.234", 1.234},
+		{"de-DE", "1,234", 1.234},
+		{"de-DE", "1.234", 1234},
+		{"fr-FR", "1,234", 1.234},
+		{"pt-BR", "1.234", 1234},
+		{"de-CH", "1.234", 1.234},
+		{"fr-CH,fr;q=0.9", "1,234", 1.234},
+
+		// Spaces and apostrophes only group thousands
+		{"fr-FR", "1 234,56", 1234.56},
+		{"fr-FR", "1 234,56", 1234.56},
+		{"en-US", "1 234", 1234},
+		{"de-CH", "1'234.5", 1234.5},
+		{"de-CH", "1’234", 1234},
+
+		// Fractions
+		{"en-US", "1/2", 0.5},
+		{"en-US", "2 3/4", 2.75},
+		{"de-DE", "2¾", 2.75},
+		{"en-US", "2 ¾", 2.75},
+		{"en-US", "½", 0.5},
+		{"en-US", "-1/4", -0.25},
+	}
+	for _, tt := range tests {
+		got, err := ParseNumber(tt.input, UsesDecimalComma(tt.locale))
+		if err != nil {
+			t.Errorf("ParseNumber(%q) in %s: %v", tt.input, tt.locale, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseNumber(%q) in %s = %v, want %v", tt.input, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestParseNumberInvalid(t *testing.T) {
+	for _, input := range []string{
+		"", "-", "abc", "1.2.3", "1,23,4", "12,34.5", "1.234,56,7",
+		"1/0", "1/", "a/2", "1e", "1e400", "2 ¾ 1", "1,234 567",
+	} {
+		for _, decimalComma := range []bool{false, true} {
+			if got, err := ParseNumber(input, decimalComma); !errors.Is(err, ErrInvalidNumber) {
+				t.Errorf("ParseNumber(%q, %v) = %v, %v, want ErrInvalidNumber", input, decimalComma, got, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,389 @@
+package converter
+
+import "math"
+
+// Unit packs for dimensions beyond the core catalog. Each pack registers
+// itself like any third-party provider would.
+
+// poundForce is one pound-force in newtons: 0.45359237 kg under standard
+// gravity.
+const poundForce = 0.45359237 * StandardGravity
+
+// torquePack is kept apart from energy: N·m and J share SI base units but
+// measure different things.
+func torquePack() UnitPack {
+	return UnitPack{
+		Name: "torque",
+		Units: withMetadata(map[string]Unit{
+			// Torque units (base = newton-meter)
+			"N·m":   {Factor: 1, Dimension: "torque", Name: "Newton-meter"},
+			"N·cm":  {Factor: 0.01, Dimension: "torque", Name: "Newton-centimeter"},
+			"kN·m":  {Factor: 1000, Dimension: "torque", Name: "Kilonewton-meter"},
+			"lb·ft": {Factor: poundForce * 0.3048, Dimension: "torque", Name: "Pound-foot"},
+			"lb·in": {Factor: poundForce * 0.0254, Dimension: "torque", Name: "Pound-inch"},
+			"kgf·m": {Factor: StandardGravity, Dimension: "torque", Name: "Kilogram-force meter"},
+			"oz·in": {Factor: poundForce / 16 * 0.0254, Dimension: "torque", Name: "Ounce-force inch"},
+		}, map[string]UnitMetadata{
+			"N·m":   meta(SystemSI, sourceSI, "SI unit of torque: a force of one newton acting one metre from the axis.", "Wheel nuts, engine torque, bicycle components", "Newton-metre"),
+			"N·cm":  meta(SystemSI, sourceSI, "One hundredth of a newton-metre.", "Small fasteners, electronics, torque screwdrivers", "Newton-metre"),
+			"kN·m":  meta(SystemSI, sourceSI, "One thousand newton-metres.", "Structural engineering, large motors", "Newton-metre"),
+			"lb·ft": meta(SystemImperial, sourceNIST, "A force of one pound-force acting one foot from the axis.", "US automotive torque specifications", "Pound-foot_(torque)"),
+			"lb·in": meta(SystemImperial, sourceNIST, "A force of one pound-force acting one inch from the axis.", "Small engines, firearms, bicycle specs", "Pound-foot_(torque)"),
+			"kgf·m": meta(SystemMetric, sourceNIST, "A force of one kilogram-force acting one metre from the axis.", "Older Japanese and European engine specifications", "Kilogram-force"),
+			"oz·in": meta(SystemImperial, sourceNIST, "A force of one ounce-force acting one inch from the axis.", "Stepper and servo motors", "Torque#Units"),
+		}),
+		Aliases: map[string]string{
+			"Nm": "N·m", "N*m": "N·m", "N.m": "N·m", "N-m": "N·m", "newton-meter": "N·m", "newton-metre": "N·m",
+			"Ncm": "N·cm", "N*cm": "N·cm", "N-cm": "N·cm",
+			"kNm": "kN·m", "kN*m": "kN·m", "kN-m": "kN·m",
+			"lbf·ft": "lb·ft", "lbf-ft": "lb·ft", "lb-ft": "lb·ft", "lb*ft": "lb·ft", "ft·lb": "lb·ft", "ft-lb": "lb·ft", "ft-lbs": "lb·ft", "pound-foot": "lb·ft",
+			"lbf·in": "lb·in", "lbf-in": "lb·in", "lb-in": "lb·in", "lb*in": "lb·in", "in·lb": "lb·in", "in-lb": "lb·in", "in-lbs": "lb·in",
+			"kgf-m": "kgf·m", "kgf*m": "kgf·m", "kg-m": "kgf·m", "kgm": "kgf·m",
+			"ozf·in": "oz·in", "oz-in": "oz·in", "oz*in": "oz·in", "in-oz": "oz·in",
+		},
+		Dimensions: map[string]string{"torque": "Torque"},
+		BaseUnits:  map[string]string{"torque": "N·m"},
+	}
+}
+
+// flowPack covers volumetric and mass flow rates, for pump and HVAC sizing.
+func flowPack() UnitPack {
+	const (
+		liter     = 0.001
+		gallon    = 0.003785411784
+		cubicFoot = 0.028316846592
+		pound     = 0.45359237
+	)
+	return UnitPack{
+		Name: "flow",
+		Units: withMetadata(map[string]Unit{
+			// Volumetric flow rate units (base = cubic meter per second)
+			"m³/s":    {Factor: 1, Dimension: "volumetric_flow", Name: "Cubic meter per second"},
+			"m³/h":    {Factor: 1.0 / 3600, Dimension: "volumetric_flow", Name: "Cubic meter per hour"},
+			"L/s":     {Factor: liter, Dimension: "volumetric_flow", Name: "Liter per second"},
+			"L/min":   {Factor: liter / 60, Dimension: "volumetric_flow", Name: "Liter per minute"},
+			"gal/min": {Factor: gallon / 60, Dimension: "volumetric_flow", Name: "Gallon per minute (US)"},
+			"CFM":     {Factor: cubicFoot / 60, Dimension: "volumetric_flow", Name: "Cubic foot per minute"},
+
+			// Mass flow rate units (base = kilogram per second)
+			"kg/s": {Factor: 1, Dimension: "mass_flow", Name: "Kilogram per second"},
+			"kg/h": {Factor: 1.0 / 3600, Dimension: "mass_flow", Name: "Kilogram per hour"},
+			"g/s":  {Factor: 0.001, Dimension: "mass_flow", Name: "Gram per second"},
+			"t/h":  {Factor: 1000.0 / 3600, Dimension: "mass_flow", Name: "Tonne per hour"},
+			"lb/s": {Factor: pound, Dimension: "mass_flow", Name: "Pound per second"},
+			"lb/h": {Factor: pound / 3600, Dimension: "mass_flow", Name: "Pound per hour"},
+		}, map[string]UnitMetadata{
+			"m³/s":    meta(SystemSI, sourceSI, "SI unit of volumetric flow rate.", "Rivers, large ducts, hydraulics", "Volumetric_flow_rate"),
+			"m³/h":    meta(SystemSI, sourceSI, "One cubic metre per hour, 1/3600 m³/s.", "Pump and ventilation ratings in Europe", "Volumetric_flow_rate"),
+			"L/s":     meta(SystemNonSI, sourceSI, "One litre per second.", "Plumbing, ventilation per room", "Volumetric_flow_rate"),
+			"L/min":   meta(SystemNonSI, sourceSI, "One litre per minute.", "Taps, shower heads, medical oxygen, small pumps", "Volumetric_flow_rate"),
+			"gal/min": meta(SystemUSCustomary, sourceNIST, "US gallons per minute (GPM).", "US pumps, irrigation, plumbing fixtures", "Gallons_per_minute"),
+			"CFM":     meta(SystemImperial, sourceNIST, "Cubic feet per minute.", "HVAC airflow, fans, compressors", "Cubic_feet_per_minute"),
+			"kg/s":    meta(SystemSI, sourceSI, "SI unit of mass flow rate.", "Process engineering, rocket engines", "Mass_flow_rate"),
+			"kg/h":    meta(SystemSI, sourceSI, "One kilogram per hour, 1/3600 kg/s.", "Boilers, fuel consumption, dosing pumps", "Mass_flow_rate"),
+			"g/s":     meta(SystemSI, sourceSI, "One gram per second.", "Engine air mass flow sensors", "Mass_flow_rate"),
+			"t/h":     meta(SystemNonSI, sourceSI, "One tonne per hour.", "Steam boilers, conveyors, bulk handling", "Mass_flow_rate"),
+			"lb/s":    meta(SystemImperial, sourceYardPnd, "One avoirdupois pound per second.", "US aerospace and process engineering", "Mass_flow_rate"),
+			"lb/h":    meta(SystemImperial, sourceYardPnd, "One avoirdupois pound per hour.", "US steam and fuel ratings", "Mass_flow_rate"),
+		}),
+		Aliases: map[string]string{
+			"m3/s": "m³/s", "m^3/s": "m³/s", "cumecs": "m³/s",
+			"m3/h": "m³/h", "m^3/h": "m³/h", "m3/hr": "m³/h", "cmh": "m³/h",
+			"l/s": "L/s", "lps": "L/s",
+			"l/min": "L/min", "lpm": "L/min", "LPM": "L/min",
+			"gpm": "gal/min", "GPM": "gal/min",
+			"cfm": "CFM", "ft³/min": "CFM", "ft3/min": "CFM",
+			"kg/sec": "kg/s",
+			"kg/hr":  "kg/h",
+			"g/sec":  "g/s",
+			"t/hr":   "t/h", "tph": "t/h",
+			"lb/sec": "lb/s", "lbs/s": "lb/s",
+			"lb/hr": "lb/h", "lbs/h": "lb/h", "lbs/hr": "lb/h", "pph": "lb/h",
+		},
+		Dimensions: map[string]string{
+			"volumetric_flow": "Flow Rate (Volume)",
+			"mass_flow":       "Flow Rate (Mass)",
+		},
+		BaseUnits: map[string]string{"volumetric_flow": "m³/s", "mass_flow": "kg/s"},
+	}
+}
+
+// accelerationPack adds acceleration, including g-force. Standard gravity
+// is "gₙ" rather than "g", which stays the gram.
+func accelerationPack() UnitPack {
+	return UnitPack{
+		Name: "acceleration",
+		Units: withMetadata(map[string]Unit{
+			// Acceleration units (base = meters per second squared)
+			"m/s²":  {Factor: 1, Dimension: "acceleration", Name: "Meter per second squared"},
+			"ft/s²": {Factor: 0.3048, Dimension: "acceleration", Name: "Foot per second squared"},
+			"gₙ":    {Factor: StandardGravity, Dimension: "acceleration", Name: "Standard gravity (g-force)"},
+			"Gal":   {Factor: 0.01, Dimension: "acceleration", Name: "Gal"},
+			"mGal":  {Factor: 0.00001, Dimension: "acceleration", Name: "Milligal"},
+		}, map[string]UnitMetadata{
+			"m/s²":  meta(SystemSI, sourceSI, "SI unit of acceleration.", "Physics, vehicle performance, vibration", "Metre_per_second_squared"),
+			"ft/s²": meta(SystemImperial, sourceYardPnd, "One foot per second squared, exactly 0.3048 m/s².", "US engineering and ballistics", "Foot_per_second_squared"),
+			"gₙ":    meta(SystemNonSI, sourceSI, "Standard acceleration of gravity, exactly 9.80665 m/s².", "G-forces in aviation, motorsport and crash tests", "Standard_gravity"),
+			"Gal":   meta(SystemMetric, sourceNIST, "CGS unit of acceleration, one centimetre per second squared.", "Gravimetry, seismology", "Gal_(unit)"),
+			"mGal":  meta(SystemMetric, sourceNIST, "One thousandth of a gal.", "Gravity anomaly surveys", "Gal_(unit)"),
+		}),
+		Aliases: map[string]string{
+			"m/s2": "m/s²", "m/s^2": "m/s²", "m/sec²": "m/s²",
+			"ft/s2": "ft/s²", "ft/s^2": "ft/s²", "fps²": "ft/s²",
+			"gn": "gₙ", "g0": "gₙ", "g₀": "gₙ", "g-force": "gₙ", "gee": "gₙ",
+			"galileo": "Gal",
+			"mgal":    "mGal", "milligal": "mGal",
+		},
+		Dimensions: map[string]string{"acceleration": "Acceleration"},
+		BaseUnits:  map[string]string{"acceleration": "m/s²"},
+	}
+}
+
+// photometricPack covers light as perceived by the eye. Illuminance,
+// luminous flux, luminous intensity and luminance are separate dimensions:
+// converting between them needs an area or solid angle.
+func photometricPack() UnitPack {
+	const squareFoot = 0.09290304
+	return UnitPack{
+		Name: "photometric",
+		Units: withMetadata(map[string]Unit{
+			// Illuminance units (base = lux)
+			"lx": {Factor: 1, Dimension: "illuminance", Name: "Lux"},
+			"fc": {Factor: 1 / squareFoot, Dimension: "illuminance", Name: "Foot-candle"},
+			"ph": {Factor: 10000, Dimension: "illuminance", Name: "Phot"},
+
+			// Luminous flux units (base = lumen)
+			"lm":    {Factor: 1, Dimension: "luminous_flux", Name: "Lumen"},
+			"klm":   {Factor: 1000, Dimension: "luminous_flux", Name: "Kilolumen"},
+			"cd·sr": {Factor: 1, Dimension: "luminous_flux", Name: "Candela steradian"},
+
+			// Luminous intensity units (base = candela)
+			"cd":  {Factor: 1, Dimension: "luminous_intensity", Name: "Candela"},
+			"mcd": {Factor: 0.001, Dimension: "luminous_intensity", Name: "Millicandela"},
+
+			// Luminance units (base = candela per square meter)
+			"cd/m²":  {Factor: 1, Dimension: "luminance", Name: "Candela per square meter"},
+			"cd/ft²": {Factor: 1 / squareFoot, Dimension: "luminance", Name: "Candela per square foot"},
+			"fL":     {Factor: 1 / (math.Pi * squareFoot), Dimension: "luminance", Name: "Foot-lambert"},
+			"sb":     {Factor: 10000, Dimension: "luminance", Name: "Stilb"},
+		}, map[string]UnitMetadata{
+			"lx":     meta(SystemSI, sourceSI, "SI unit of illuminance: one lumen per square metre.", "Light meters, workplace lighting standards", "Lux"),
+			"fc":     meta(SystemImperial, sourceNIST, "One lumen per square foot, about 10.764 lux.", "US lighting design, photography", "Foot-candle"),
+			"ph":     meta(SystemMetric, sourceNIST, "CGS unit of illuminance: one lumen per square centimetre.", "Older photometry literature", "Phot"),
+			"lm":     meta(SystemSI, sourceSI, "SI unit of luminous flux: one candela over one steradian.", "Light bulb and projector brightness", "Lumen_(unit)"),
+			"klm":    meta(SystemSI, sourceSI, "One thousand lumens.", "Floodlights, stadium and street lighting", "Lumen_(unit)"),
+			"cd·sr":  meta(SystemSI, sourceSI, "Candela times steradian, the definition of the lumen.", "Photometric calculations", "Lumen_(unit)"),
+			"cd":     meta(SystemSI, sourceSI, "SI base unit of luminous intensity.", "Flashlights, LEDs, lamp beam ratings", "Candela"),
+			"mcd":    meta(SystemSI, sourceSI, "One thousandth of a candela.", "Indicator LED datasheets", "Candela"),
+			"cd/m²":  meta(SystemSI, sourceSI, "SI unit of luminance, also called the nit.", "Display and screen brightness", "Candela_per_square_metre"),
+			"cd/ft²": meta(SystemImperial, sourceNIST, "One candela per square foot.", "US lighting engineering", "Candela_per_square_metre"),
+			"fL":     meta(SystemImperial, sourceNIST, "Luminance of a surface emitting one lumen per square foot, 1/π cd/ft².", "Cinema screen brightness", "Foot-lambert"),
+			"sb":     meta(SystemMetric, sourceNIST, "CGS unit of luminance: one candela per square centimetre.", "Older photometry literature", "Stilb"),
+		}),
+		Aliases: map[string]string{
+			"lux": "lx", "footcandle": "fc", "foot-candle": "fc", "ftcd": "fc", "lm/ft²": "fc", "lm/ft2": "fc", "lm/m²": "lx", "lm/m2": "lx",
+			"lumen": "lm", "lumens": "lm",
+			"cd*sr": "cd·sr", "cd.sr": "cd·sr",
+			"candela": "cd",
+			"nit":     "cd/m²", "nits": "cd/m²", "nt": "cd/m²", "cd/m2": "cd/m²",
+			"cd/ft2": "cd/ft²",
+			"ftL":    "fL", "foot-lambert": "fL",
+		},
+		Dimensions: map[string]string{
+			"illuminance":        "Illuminance",
+			"luminous_flux":      "Luminous Flux",
+			"luminous_intensity": "Luminous Intensity",
+			"luminance":          "Luminance",
+		},
+		BaseUnits: map[string]string{
+			"illuminance":        "lx",
+			"luminous_flux":      "lm", // Not cd·sr, which has the same factor
+			"luminous_intensity": "cd",
+			"luminance":          "cd/m²",
+		},
+	}
+}
+
+// magneticPack adds magnetic flux density. The gauss is the CGS unit
+// still common in magnet and electronics datasheets.
+func magneticPack() UnitPack {
+	return UnitPack{
+		Name: "magnetic",
+		Units: withMetadata(map[string]Unit{
+			// Magnetic flux density units (base = tesla)
+			"T":  {Factor: 1, Dimension: "magnetic_flux_density", Name: "Tesla"},
+			"mT": {Factor: 1e-3, Dimension: "magnetic_flux_density", Name: "Millitesla"},
+			"µT": {Factor: 1e-6, Dimension: "magnetic_flux_density", Name: "Microtesla"},
+			"nT": {Factor: 1e-9, Dimension: "magnetic_flux_density", Name: "Nanotesla"},
+			"G":  {Factor: 1e-4, Dimension: "magnetic_flux_density", Name: "Gauss"},
+			"kG": {Factor: 1e-1, Dimension: "magnetic_flux_density", Name: "Kilogauss"},
+			"mG": {Factor: 1e-7, Dimension: "magnetic_flux_density", Name: "Milligauss"},
+		}, map[string]UnitMetadata{
+			"T":  meta(SystemSI, sourceSI, "SI unit of magnetic flux density: one weber per square metre.", "MRI scanners, particle accelerators", "Tesla_(unit)"),
+			"mT": meta(SystemSI, sourceSI, "One thousandth of a tesla.", "Permanent magnets, Hall sensors", "Tesla_(unit)"),
+			"µT": meta(SystemSI, sourceSI, "One millionth of a tesla.", "Earth's magnetic field, compasses, magnetometers", "Tesla_(unit)"),
+			"nT": meta(SystemSI, sourceSI, "One billionth of a tesla, also called the gamma.", "Geomagnetic surveys, space weather", "Tesla_(unit)"),
+			"G":  meta(SystemMetric, sourceNIST, "CGS unit of magnetic flux density, exactly 10⁻⁴ tesla.", "Magnet datasheets, fridge magnets", "Gauss_(unit)"),
+			"kG": meta(SystemMetric, sourceNIST, "One thousand gauss, 0.1 tesla.", "Strong permanent magnets, MRI literature", "Gauss_(unit)"),
+			"mG": meta(SystemMetric, sourceNIST, "One thousandth of a gauss.", "EMF exposure measurements", "Gauss_(unit)"),
+		}),
+		Aliases: map[string]string{
+			"tesla": "T", "millitesla": "mT",
+			"uT": "µT", "μT": "µT", "microtesla": "µT",
+			"nanotesla": "nT", "gamma": "nT", "γ": "nT",
+			"gauss": "G", "Gs": "G",
+			"kilogauss": "kG", "kGs": "kG",
+			"milligauss": "mG", "mGs": "mG",
+		},
+		Dimensions: map[string]string{"magnetic_flux_density": "Magnetic Flux Density"},
+		BaseUnits:  map[string]string{"magnetic_flux_density": "T"},
+	}
+}
+
+// energyPack adds the energy units of utility bills, fuels and physics,
+// and the power units that go with them.
+func energyPack() UnitPack {
+	const btu = 1055.05585262 // International Table BTU
+	return UnitPack{
+		Name: "energy",
+		Units: withMetadata(map[string]Unit{
+			"Wh":    {Factor: 3600, Dimension: "energy", Name: "Watt-hour"},
+			"kWh":   {Factor: 3.6e6, Dimension: "energy", Name: "Kilowatt-hour"},
+			"MWh":   {Factor: 3.6e9, Dimension: "energy", Name: "Megawatt-hour"},
+			"kJ":    {Factor: 1e3, Dimension: "energy", Name: "Kilojoule"},
+			"MJ":    {Factor: 1e6, Dimension: "energy", Name: "Megajoule"},
+			"BTU":   {Factor: btu, Dimension: "energy", Name: "British thermal unit"},
+			"therm": {Factor: 1e5 * btu, Dimension: "energy", Name: "Therm"},
+			"eV":    {Factor: 1.602176634e-19, Dimension: "energy", Name: "Electronvolt"},
+
+			"kW": {Factor: 1e3, Dimension: "power", Name: "Kilowatt"},
+			"MW": {Factor: 1e6, Dimension: "power", Name: "Megawatt"},
+		}, map[string]UnitMetadata{
+			"Wh":    meta(SystemNonSI, sourceNIST, "Energy of one watt sustained for one hour, 3600 J.", "Battery capacities", "Kilowatt-hour#Watt-hour_multiples"),
+			"kWh":   meta(SystemNonSI, sourceNIST, "Energy of one kilowatt sustained for one hour, 3.6 MJ.", "Electricity bills, electric vehicle batteries", "Kilowatt-hour"),
+			"MWh":   meta(SystemNonSI, sourceNIST, "One thousand kilowatt-hours.", "Power plant output, industrial consumption", "Kilowatt-hour#Watt-hour_multiples"),
+			"kJ":    meta(SystemSI, sourceSI, "One thousand joules.", "Nutrition labels outside the US, physics", "Joule"),
+			"MJ":    meta(SystemSI, sourceSI, "One million joules.", "Fuel energy content, industrial heat", "Joule"),
+			"BTU":   meta(SystemImperial, sourceNIST, "International Table British thermal unit, about 1055 J.", "Heating and air conditioning ratings, natural gas", "British_thermal_unit"),
+			"therm": meta(SystemUSCustomary, sourceNIST, "100 000 BTU, about 105.5 MJ.", "US and UK natural gas bills", "Therm"),
+			"eV":    meta(SystemNonSI, sourceSI, "Energy gained by an electron across one volt, exactly 1.602176634×10⁻¹⁹ J.", "Particle physics, chemistry, semiconductors", "Electronvolt"),
+			"kW":    meta(SystemSI, sourceSI, "One thousand watts.", "Appliances, heaters, car engines, solar installations", "Watt#Kilowatt"),
+			"MW":    meta(SystemSI, sourceSI, "One million watts.", "Power plants, wind turbines, data centers", "Watt#Megawatt"),
+		}),
+		Aliases: map[string]string{
+			"kwh": "kWh", "KWH": "kWh", "kW·h": "kWh", "kW h": "kWh", "kilowatt-hour": "kWh",
+			"wh": "Wh", "W·h": "Wh",
+			// No "mwh" or "mw": a lowercase m is milli, not mega
+			"megawatt-hour": "MWh", "megawatt hour": "MWh",
+			"Btu": "BTU", "btu": "BTU",
+			"thm": "therm", "therms": "therm",
+			"electronvolt": "eV",
+			"kw":           "kW", "KW": "kW", "kilowatt": "kW",
+			"megawatt": "MW",
+		},
+	}
+}
+
+// dataPack adds IEC binary units, the decimal kilobyte and data rates.
+// The core KB, MB and GB are binary (JEDEC), and so are TB, KB/s, MB/s and
+// GB/s, which are spelled the same way; the other data rates follow
+// networking usage and are decimal unless they carry an IEC "i".
+func dataPack() UnitPack {
+	return UnitPack{
+		Name: "data",
+		Units: withMetadata(map[string]Unit{
+			"kB":  {Factor: 1e3, Dimension: "data_storage", Name: "Kilobyte (decimal)"},
+			"TB":  {Factor: 1 << 40, Dimension: "data_storage", Name: "Terabyte (binary, 2⁴⁰ bytes)"},
+			"KiB": {Factor: 1 << 10, Dimension: "data_storage", Name: "Kibibyte"},
+			"MiB": {Factor: 1 << 20, Dimension: "data_storage", Name: "Mebibyte"},
+			"GiB": {Factor: 1 << 30, Dimension: "data_storage", Name: "Gibibyte"},
+			"TiB": {Factor: 1 << 40, Dimension: "data_storage", Name: "Tebibyte"},
+
+			// Data rate units (base = bytes per second)
+			"bit/s":  {Factor: 0.125, Dimension: "data_rate", Name: "Bit per second"},
+			"kbit/s": {Factor: 125, Dimension: "data_rate", Name: "Kilobit per second"},
+			"Mbit/s": {Factor: 125e3, Dimension: "data_rate", Name: "Megabit per second"},
+			"Gbit/s": {Factor: 125e6, Dimension: "data_rate", Name: "Gigabit per second"},
+			"B/s":    {Factor: 1, Dimension: "data_rate", Name: "Byte per second"},
+			"kB/s":   {Factor: 1e3, Dimension: "data_rate", Name: "Kilobyte per second"},
+			"KB/s":   {Factor: 1 << 10, Dimension: "data_rate", Name: "Kilobyte per second (binary)"},
+			"MB/s":   {Factor: 1 << 20, Dimension: "data_rate", Name: "Megabyte per second (binary)"},
+			"GB/s":   {Factor: 1 << 30, Dimension: "data_rate", Name: "Gigabyte per second (binary)"},
+			"KiB/s":  {Factor: 1 << 10, Dimension: "data_rate", Name: "Kibibyte per second"},
+			"MiB/s":  {Factor: 1 << 20, Dimension: "data_rate", Name: "Mebibyte per second"},
+		}, map[string]UnitMetadata{
+			"kB":     meta(SystemSI, "IEC 80000-13", "1000 bytes (decimal kilobyte).", "Network transfers, storage marketing", "Kilobyte"),
+			"TB":     meta(SystemIEC, sourceJEDEC, "1024 gigabytes (binary terabyte, like the other JEDEC units here).", "Disk and backup sizes", "Terabyte"),
+			"KiB":    meta(SystemIEC, "IEC 80000-13", "Kibibyte: exactly 1024 bytes.", "Memory, file systems", "Kibibyte"),
+			"MiB":    meta(SystemIEC, "IEC 80000-13", "Mebibyte: exactly 1024² bytes.", "Memory, file systems", "Mebibyte"),
+			"GiB":    meta(SystemIEC, "IEC 80000-13", "Gibibyte: exactly 1024³ bytes.", "RAM sizes, operating systems", "Gibibyte"),
+			"TiB":    meta(SystemIEC, "IEC 80000-13", "Tebibyte: exactly 1024⁴ bytes.", "Disk sizes as reported by operating systems", "Tebibyte"),
+			"bit/s":  meta(SystemIEC, "IEC 80000-13", "One bit per second.", "Serial links, modems", "Bit_per_second"),
+			"kbit/s": meta(SystemSI, "IEC 80000-13", "1000 bits per second.", "Audio bitrates, IoT links", "Bit_per_second"),
+			"Mbit/s": meta(SystemSI, "IEC 80000-13", "One million bits per second.", "Internet plans, Wi-Fi, video bitrates", "Bit_per_second"),
+			"Gbit/s": meta(SystemSI, "IEC 80000-13", "One billion bits per second.", "Fibre internet, Ethernet", "Bit_per_second"),
+			"B/s":    meta(SystemIEC, "IEC 80000-13", "One byte per second.", "Transfer progress indicators", "Data-rate_units"),
+			"kB/s":   meta(SystemSI, "IEC 80000-13", "1000 bytes per second.", "Download managers", "Data-rate_units"),
+			"KB/s":   meta(SystemIEC, sourceJEDEC, "1024 bytes per second, the rate of the binary KB.", "Older download dialogs and tools", "Data-rate_units"),
+			"MB/s":   meta(SystemIEC, sourceJEDEC, "1024² bytes per second, the rate of the binary MB.", "Disk throughput, downloads", "Data-rate_units"),
+			"GB/s":   meta(SystemIEC, sourceJEDEC, "1024³ bytes per second, the rate of the binary GB.", "SSD and memory bandwidth", "Data-rate_units"),
+			"KiB/s":  meta(SystemIEC, "IEC 80000-13", "1024 bytes per second.", "Tools that report binary rates", "Data-rate_units"),
+			"MiB/s":  meta(SystemIEC, "IEC 80000-13", "1024² bytes per second.", "Tools that report binary rates", "Data-rate_units"),
+		}),
+		Aliases: map[string]string{
+			"b/s": "bit/s", // "bps" is the basis point
+
+			// No "mbps", and so no "gbps": a lowercase m is milli, not mega
+			"kbps": "kbit/s", "Kbps": "kbit/s", "kb/s": "kbit/s",
+			"Mbps": "Mbit/s", "Mb/s": "Mbit/s",
+			"Gbps": "Gbit/s", "Gb/s": "Gbit/s",
+			"Bps":  "B/s",
+			"kBps": "kB/s",
+			"MBps": "MB/s",
+			"GBps": "GB/s",
+		},
+		Dimensions: map[string]string{"data_rate": "Data Rate"},
+		BaseUnits:  map[string]string{"data_rate": "B/s"},
+	}
+}
+
+// ratioPack adds dimensionless ratios. The base unit is the plain fraction.
+func ratioPack() UnitPack {
+	return UnitPack{
+		Name: "ratio",
+		Units: withMetadata(map[string]Unit{
+			"ratio": {Factor: 1, Dimension: "ratio", Name: "Ratio"},
+			"%":     {Factor: 1e-2, Dimension: "ratio", Name: "Percent"},
+			"‰":     {Factor: 1e-3, Dimension: "ratio", Name: "Per mille"},
+			"bp":    {Factor: 1e-4, Dimension: "ratio", Name: "Basis point"},
+			"ppm":   {Factor: 1e-6, Dimension: "ratio", Name: "Part per million"},
+			"ppb":   {Factor: 1e-9, Dimension: "ratio", Name: "Part per billion"},
+		}, map[string]UnitMetadata{
+			"ratio": meta(SystemSI, "ISO 80000-1", "A plain fraction of one (0.25 = 25 %).", "Probabilities, efficiencies, spreadsheets", "Ratio"),
+			"%":     meta(SystemNonSI, "ISO 80000-1", "One hundredth.", "Interest rates, discounts, concentrations", "Percentage"),
+			"‰":     meta(SystemNonSI, "ISO 80000-1", "One thousandth.", "Salinity, blood alcohol, tax rates", "Per_mille"),
+			"bp":    meta(SystemNonSI, "Financial convention", "One hundredth of a percent.", "Interest rate and yield changes", "Basis_point"),
+			"ppm":   meta(SystemNonSI, "IUPAC", "One millionth.", "Trace gases, water quality, oscillator accuracy", "Parts-per_notation"),
+			"ppb":   meta(SystemNonSI, "IUPAC", "One billionth (10⁻⁹).", "Pollutants, trace contaminants", "Parts-per_notation"),
+		}),
+		Aliases: map[string]string{
+			"fraction": "ratio",
+			"percent":  "%", "pct": "%",
+			"permille": "‰", "per mille": "‰", "per mil": "‰",
+			"bps": "bp", "basis point": "bp", "basis points": "bp",
+		},
+		Dimensions: map[string]string{"ratio": "Ratio"},
+		BaseUnits:  map[string]string{"ratio": "ratio"},
+	}
+}
+
+func init() {
+	RegisterProvider(torquePack())
+	RegisterProvider(flowPack())
+	RegisterProvider(accelerationPack())
+	RegisterProvider(photometricPack())
+	RegisterProvider(magneticPack())
+	RegisterProvider(energyPack())
+	RegisterProvider(dataPack())
+	RegisterProvider(ratioPack())
+}
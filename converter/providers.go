@@ -0,0 +1,168 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// UnitProvider supplies a pack of units to the converter. Packs are
+// registered with RegisterProvider, usually from an init function, and are
+// applied when a converter is created or its registry is reloaded.
+type UnitProvider interface {
+	Provide() UnitPack
+}
+
+// UnitPack is a named set of units together with their aliases and the
+// display names and base units of any dimensions they introduce. A
+// UnitPack is itself a UnitProvider, so static packs can be registered
+// directly.
+type UnitPack struct {
+	Name       string            // Unique pack name, e.g. "core" or "cooking"
+	Units      map[string]Unit   // Units keyed by symbol
+	Aliases    map[string]string // Alternative spellings mapped to unit symbols
+	Dimensions map[string]string // Dimension keys mapped to display names
+	BaseUnits  map[string]string // Dimension keys mapped to the symbol of their base unit
+}
+
+// Provide returns the pack itself.
+func (p UnitPack) Provide() UnitPack {
+	return p
+}
+
+// Conversion maps values between a unit and the base unit of its dimension,
+// for units that can't be described by a factor and an offset.
+type Conversion interface {
+	ToBase(value float64) float64
+	FromBase(value float64) float64
+}
+
+// ContextConversion is a Conversion that may block, for instance on a
+// remote rate provider or storage. ConvertContext and ConvertRefContext use
+// the context methods so the conversion honors cancellation and deadlines
+// and can propagate trace context (see TraceParent); the plain methods are
+// used by the context-free API.
+type ContextConversion interface {
+	Conversion
+	ToBaseContext(ctx context.Context, value float64) (float64, error)
+	FromBaseContext(ctx context.Context, value float64) (float64, error)
+}
+
+// ConversionFuncs adapts a pair of functions to the Conversion interface.
+type ConversionFuncs struct {
+	To   func(value float64) float64 // Unit -> base unit
+	From func(value float64) float64 // Base unit -> unit
+}
+
+// ToBase implements Conversion.
+func (c ConversionFuncs) ToBase(value float64) float64 {
+	return c.To(value)
+}
+
+// FromBase implements Conversion.
+func (c ConversionFuncs) FromBase(value float64) float64 {
+	return c.From(value)
+}
+
+var (
+	providersMu       sync.Mutex
+	providers         []UnitProvider
+	optionalProviders = make(map[string]UnitProvider) // By pack name
+)
+
+// RegisterProvider makes a unit pack available to converters. It panics if
+// p is nil; conflicting packs are reported when the registry is built.
+func RegisterProvider(p UnitProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if p == nil {
+		panic("goverter: RegisterProvider provider is nil")
+	}
+	providers = append(providers, p)
+}
+
+// RegisterOptionalProvider makes a unit pack available to deployments that
+// enable it by name (the units.packs setting). It panics if p is nil or its
+// pack name is already taken by another optional pack.
+func RegisterOptionalProvider(p UnitProvider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if p == nil {
+		panic("goverter: RegisterOptionalProvider provider is nil")
+	}
+	name := p.Provide().Name
+	if _, dup := optionalProviders[name]; dup {
+		panic("goverter: optional unit pack " + name + " registered twice")
+	}
+	optionalProviders[name] = p
+}
+
+// OptionalPacks returns the names of the optional unit packs, sorted.
+func OptionalPacks() []string {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	names := make([]string, 0, len(optionalProviders))
+	for name := range optionalProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// optionalProvider returns the optional pack with the given name.
+func optionalProvider(name string) (UnitProvider, bool) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	p, ok := optionalProviders[name]
+	return p, ok
+}
+
+// registeredProviders returns a copy of the registered providers.
+func registeredProviders() []UnitProvider {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	return append([]UnitProvider(nil), providers...)
+}
+
+// applyPack merges a pack into the registry. Packs may not redefine units,
+// aliases or dimensions contributed by another pack.
+func (r *registry) applyPack(pack UnitPack) error {
+	if pack.Name == "" {
+		return fmt.Errorf("unit pack with empty name")
+	}
+	if r.packs[pack.Name] {
+		return fmt.Errorf("unit pack %s registered twice", pack.Name)
+	}
+	r.packs[pack.Name] = true
+
+	for symbol, unit := range pack.Units {
+		if _, ok := r.units[symbol]; ok {
+			return fmt.Errorf("unit pack %s: unit %s is already defined", pack.Name, symbol)
+		}
+		r.units[symbol] = unit
+	}
+	for alias, symbol := range pack.Aliases {
+		if _, ok := r.aliases[alias]; ok {
+			return fmt.Errorf("unit pack %s: alias %s is already defined", pack.Name, alias)
+		}
+		r.aliases[alias] = symbol
+	}
+	for dim, name := range pack.Dimensions {
+		if _, ok := r.dimensionNames[dim]; ok {
+			return fmt.Errorf("unit pack %s: dimension %s is already defined", pack.Name, dim)
+		}
+		r.dimensionNames[dim] = name
+	}
+	for dim, symbol := range pack.BaseUnits {
+		if _, ok := r.baseUnits[dim]; ok {
+			return fmt.Errorf("unit pack %s: base unit of %s is already defined", pack.Name, dim)
+		}
+		r.baseUnits[dim] = symbol
+	}
+	return nil
+}
+
+func init() {
+	RegisterProvider(UnitPack{Name: "core", Units: withMetadata(builtinUnits(), coreMetadata), BaseUnits: builtinBaseUnits()})
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// maxRangePoints bounds the length of a generated range series.
+const maxRangePoints = 1000
+
+// RangePoint is one step of a converted range.
+type RangePoint struct {
+	Value  float64 `json:"value"`
+	Result float64 `json:"result"`
+}
+
+// ConvertRange converts the endpoints of [min, max] and, when step is
+// positive, every step in between. The last point is always max.
+func (uc *UnitConverter) ConvertRange(ctx context.Context, min, max, step float64, from, to string) (lo, hi RangePoint, series []RangePoint, err error) {
+	for _, bound := range []struct {
+		name  string
+		value float64
+	}{{"min", min}, {"max", max}, {"step", step}} {
+		if math.IsNaN(bound.value) || math.IsInf(bound.value, 0) {
+			return lo, hi, nil, fmt.Errorf("%s must be a finite number", bound.name)
+		}
+	}
+	if math.IsInf(max-min, 0) {
+		return lo, hi, nil, fmt.Errorf("range from %g to %g is too wide", min, max)
+	}
+	if min > max {
+		return lo, hi, nil, fmt.Errorf("min (%g) must not be greater than max (%g)", min, max)
+	}
+	fromRef, toRef := uc.Resolve(from), uc.Resolve(to)
+	if !fromRef.Valid() {
+		return lo, hi, nil, &UnknownUnitError{Symbol: from, Role: "source", Suggestions: uc.Suggest(from)}
+	}
+	if !toRef.Valid() {
+		return lo, hi, nil, &UnknownUnitError{Symbol: to, Role: "target", Suggestions: uc.Suggest(to)}
+	}
+
+	convert := func(value float64) (RangePoint, error) {
+		result, err := uc.ConvertRefContext(ctx, value, fromRef, toRef)
+		return RangePoint{Value: value, Result: result}, err
+	}
+	if lo, err = convert(min); err != nil {
+		return lo, hi, nil, err
+	}
+	if hi, err = convert(max); err != nil {
+		return lo, hi, nil, err
+	}
+	if step <= 0 {
+		return lo, hi, nil, nil
+	}
+
+	points := math.Floor((max-min)/step) + 1
+	if !(points <= maxRangePoints) {
+		return lo, hi, nil, fmt.Errorf("range would produce more than %d points, use a larger step", maxRangePoints)
+	}
+	series = make([]RangePoint, 0, int(points)+1)
+	for i := 0; i < int(points); i++ {
+		// Multiply rather than accumulate so rounding errors don't build up
+		point, err := convert(min + float64(i)*step)
+		if err != nil {
+			return lo, hi, nil, err
+		}
+		series = append(series, point)
+	}
+	if series[len(series)-1].Value != max {
+		series = append(series, hi)
+	}
+	return lo, hi, series, nil
+}
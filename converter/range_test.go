@@ -0,0 +1,58 @@
+package converter
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestConvertRange(t *testing.T) {
+	uc := NewUnitConverter()
+	lo, hi, series, err := uc.ConvertRange(context.Background(), 0, 2.5, 1, "km", "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lo != (RangePoint{0, 0}) || hi != (RangePoint{2.5, 2500}) {
+		t.Errorf("endpoints = %v, %v", lo, hi)
+	}
+	want := []RangePoint{{0, 0}, {1, 1000}, {2, 2000}, {2.5, 2500}}
+	if len(series) != len(want) {
+		t.Fatalf("series = %v, want %v", series, want)
+	}
+	for i := range want {
+		if series[i] != want[i] {
+			t.Errorf("series[%d] = %v, want %v", i, series[i], want[i])
+		}
+	}
+
+	// Without a step only the endpoints are converted
+	if _, _, series, err := uc.ConvertRange(context.Background(), 1, 5, 0, "km", "m"); err != nil || series != nil {
+		t.Errorf("no step: series %v, error %v", series, err)
+	}
+}
+
+func TestConvertRangeInvalid(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		name           string
+		min, max, step float64
+		want           string
+	}{
+		{"reversed", 5, 1, 1, "min (5) must not be greater than max (1)"},
+		{"infinite step", 0, 1, math.Inf(1), "step must be a finite number"},
+		{"NaN step", 0, 1, math.NaN(), "step must be a finite number"},
+		{"infinite max", 0, math.Inf(1), 1, "max must be a finite number"},
+		{"NaN min", math.NaN(), 1, 1, "min must be a finite number"},
+		{"overflowing span", -1e308, 1e308, math.Inf(1), "step must be a finite number"},
+		{"overflowing span with a step", -1e308, 1e308, 1e300, "range from -1e+308 to 1e+308 is too wide"},
+		{"too many points", 0, 1e6, 1, "range would produce more than 1000 points, use a larger step"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := uc.ConvertRange(context.Background(), tt.min, tt.max, tt.step, "m", "km")
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ConvertRange error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
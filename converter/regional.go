@@ -0,0 +1,140 @@
+package converter
+
+// Optional regional and historical unit packs. They are not loaded unless
+// enabled with the units.packs setting, so deployments only list the units
+// their users need.
+
+// Systems of the optional packs.
+const (
+	systemUKImperial = "Imperial (UK)"
+	systemShakkanho  = "Shakkanhō (traditional Japanese)"
+	systemHistorical = "English customary (historical)"
+)
+
+// UK imperial and US liquid measures, both in cubic metres.
+const (
+	ukGallon = 0.00454609
+	ukPint   = ukGallon / 8
+	usPint   = 0.003785411784 / 8
+)
+
+// imperialPack adds UK imperial volumes next to their US namesakes, and
+// the stone.
+func imperialPack() UnitPack {
+	return UnitPack{
+		Name: "imperial",
+		Units: withMetadata(map[string]Unit{
+			"gal_uk":   {Factor: ukGallon, Dimension: "volume", Name: "Gallon (UK)"},
+			"qt_uk":    {Factor: ukPint * 2, Dimension: "volume", Name: "Quart (UK)"},
+			"pt_uk":    {Factor: ukPint, Dimension: "volume", Name: "Pint (UK)"},
+			"fl_oz_uk": {Factor: ukGallon / 160, Dimension: "volume", Name: "Fluid Ounce (UK)"},
+			"qt_us":    {Factor: usPint * 2, Dimension: "volume", Name: "Quart (US)"},
+			"pt_us":    {Factor: usPint, Dimension: "volume", Name: "Pint (US)"},
+			"st":       {Factor: 14 * 453.59237, Dimension: "mass", Name: "Stone"},
+		}, map[string]UnitMetadata{
+			"gal_uk":   meta(systemUKImperial, "Weights and Measures Act 1985", "Exactly 4.54609 litres, about 1.2 US gallons.", "Fuel economy in the UK, older recipes", "Gallon"),
+			"qt_uk":    meta(systemUKImperial, "Weights and Measures Act 1985", "Two UK pints.", "Older British recipes", "Quart"),
+			"pt_uk":    meta(systemUKImperial, "Weights and Measures Act 1985", "One eighth of a UK gallon, 568 mL.", "Beer and milk in the UK and Ireland", "Pint"),
+			"fl_oz_uk": meta(systemUKImperial, "Weights and Measures Act 1985", "One twentieth of a UK pint, about 28.4 mL.", "British recipes and drink labels", "Fluid_ounce"),
+			"qt_us":    meta(SystemUSCustomary, sourceNIST, "A quarter of a US gallon.", "US recipes, motor oil", "Quart"),
+			"pt_us":    meta(SystemUSCustomary, sourceNIST, "One eighth of a US gallon, 473 mL.", "US recipes, beverages", "Pint"),
+			"st":       meta(systemUKImperial, "Weights and Measures Act 1985", "14 pounds.", "Body weight in the UK and Ireland", "Stone_(unit)"),
+		}),
+		Aliases: map[string]string{
+			"imperial gallon": "gal_uk", "uk gallon": "gal_uk",
+			"uk quart": "qt_uk",
+			"uk pint":  "pt_uk", "imperial pint": "pt_uk",
+			"uk fl oz": "fl_oz_uk",
+			"us quart": "qt_us", "qt": "qt_us",
+			"us pint": "pt_us", "pt": "pt_us",
+			"stone": "st",
+		},
+	}
+}
+
+// historicalPack adds the old English surveying lengths.
+func historicalPack() UnitPack {
+	return UnitPack{
+		Name: "historical",
+		Units: withMetadata(map[string]Unit{
+			"fur":    {Factor: 201.168, Dimension: "length", Name: "Furlong"},
+			"ch":     {Factor: 20.1168, Dimension: "length", Name: "Chain"},
+			"rd":     {Factor: 5.0292, Dimension: "length", Name: "Rod"},
+			"league": {Factor: 4828.032, Dimension: "length", Name: "League"},
+		}, map[string]UnitMetadata{
+			"fur":    meta(systemHistorical, sourceYardPnd, "220 yards, an eighth of a mile.", "Horse racing", "Furlong"),
+			"ch":     meta(systemHistorical, sourceYardPnd, "22 yards (Gunter's chain).", "Land surveys, cricket pitches", "Chain_(unit)"),
+			"rd":     meta(systemHistorical, sourceYardPnd, "5.5 yards, a quarter of a chain.", "Old deeds and land records", "Rod_(unit)"),
+			"league": meta(systemHistorical, sourceYardPnd, "Three miles.", "Literature, old maps", "League_(unit)"),
+		}),
+		Aliases: map[string]string{
+			"furlong": "fur", "chain": "ch", "rod": "rd", "perch": "rd", "pole": "rd",
+		},
+	}
+}
+
+// Base lengths of the shakkanhō system, in metres and square metres.
+const (
+	shaku = 10.0 / 33
+	tsubo = 36 * shaku * shaku // one ken squared
+	gou   = 2401.0 / 13310000  // in m³
+)
+
+// japanesePack adds the traditional Japanese units still used for rooms,
+// land, rice and sake.
+func japanesePack() UnitPack {
+	return UnitPack{
+		Name: "japanese",
+		Units: withMetadata(map[string]Unit{
+			"sun":   {Factor: shaku / 10, Dimension: "length", Name: "Sun"},
+			"shaku": {Factor: shaku, Dimension: "length", Name: "Shaku"},
+			"ken":   {Factor: 6 * shaku, Dimension: "length", Name: "Ken"},
+			"ri":    {Factor: 12960 * shaku, Dimension: "length", Name: "Ri"},
+			"tsubo": {Factor: tsubo, Dimension: "area", Name: "Tsubo"},
+			"gō":    {Factor: gou, Dimension: "volume", Name: "Gō"},
+			"shō":   {Factor: 10 * gou, Dimension: "volume", Name: "Shō"},
+			"monme": {Factor: 3.75, Dimension: "mass", Name: "Monme"},
+			"kan":   {Factor: 3750, Dimension: "mass", Name: "Kan"},
+		}, map[string]UnitMetadata{
+			"sun":   meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "A tenth of a shaku, about 3.03 cm.", "Traditional crafts, shoe and doll sizes", "Japanese_units_of_measurement"),
+			"shaku": meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "10/33 of a metre, about 30.3 cm.", "Carpentry, traditional architecture", "Shaku_(unit)"),
+			"ken":   meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "Six shaku, about 1.82 m.", "Spacing of pillars, tatami layouts", "Ken_(unit)"),
+			"ri":    meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "12960 shaku, about 3.93 km.", "Historical road distances", "Ri_(unit)"),
+			"tsubo": meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "One square ken, about 3.31 m² (two tatami mats).", "Floor space and land prices in Japan", "Tsubo"),
+			"gō":    meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "About 180 mL.", "Rice portions, sake", "Gō_(unit)"),
+			"shō":   meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "Ten gō, about 1.8 L.", "Sake bottles (isshōbin)", "Shō_(unit)"),
+			"monme": meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "Exactly 3.75 grams.", "Pearls", "Monme"),
+			"kan":   meta(systemShakkanho, "Japanese Weights and Measures Act (1891)", "1000 monme, 3.75 kg.", "Historical weights of goods", "Kan_(unit)"),
+		}),
+		Aliases: map[string]string{
+			"go": "gō", "gou": "gō", "sho": "shō", "shou": "shō", "momme": "monme",
+		},
+	}
+}
+
+// nauticalPack adds the nautical lengths to go with the core knot.
+func nauticalPack() UnitPack {
+	return UnitPack{
+		Name: "nautical",
+		Units: withMetadata(map[string]Unit{
+			"nmi":   {Factor: 1852, Dimension: "length", Name: "Nautical Mile"},
+			"cable": {Factor: 185.2, Dimension: "length", Name: "Cable"},
+			"ftm":   {Factor: 1.8288, Dimension: "length", Name: "Fathom"},
+		}, map[string]UnitMetadata{
+			"nmi":   meta(SystemNonSI, "International Hydrographic Conference (1929)", "Exactly 1852 metres, about one minute of latitude.", "Navigation at sea and in the air", "Nautical_mile"),
+			"cable": meta(SystemNonSI, "International Hydrographic Conference (1929)", "A tenth of a nautical mile.", "Distances between ships", "Cable_length"),
+			"ftm":   meta(SystemImperial, sourceYardPnd, "Six feet.", "Water depth on charts", "Fathom"),
+		}),
+		Aliases: map[string]string{
+			"NM": "nmi", "nautical mile": "nmi", "nautical miles": "nmi",
+			"cable length": "cable", "fathom": "ftm", "fathoms": "ftm",
+		},
+	}
+}
+
+func init() {
+	RegisterOptionalProvider(imperialPack())
+	RegisterOptionalProvider(historicalPack())
+	RegisterOptionalProvider(japanesePack())
+	RegisterOptionalProvider(nauticalPack())
+}
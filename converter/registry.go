@@ -0,0 +1,473 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// registry is an immutable snapshot of every known unit. The converter swaps
+// it wholesale on reload, so readers never observe a partially applied update.
+type registry struct {
+	units          map[string]Unit
+	aliases        map[string]string // Alias -> unit symbol
+	dimensionNames map[string]string // Display names of dimensions added by packs and files
+	baseUnits      map[string]string // Declared base unit symbol of each dimension
+	packs          map[string]bool   // Names of the unit packs applied
+	version        string            // Content hash, served as the catalog ETag
+
+	// Indexes built once per registry; shared with callers, never modified
+	byDimension     map[string]map[string]Unit // Dimension -> symbol -> unit
+	dimensions      []string                   // Sorted dimension keys
+	dimensionLabels map[string]string          // Display name of every dimension
+	bases           map[string]string          // Base unit of every dimension that has one
+	handles         []resolvedUnit             // Units addressed by UnitRef
+	handleIndex     map[string]int             // Symbol or alias -> handle
+}
+
+func newRegistry() *registry {
+	return &registry{
+		units:          make(map[string]Unit),
+		aliases:        make(map[string]string),
+		dimensionNames: make(map[string]string),
+		baseUnits:      make(map[string]string),
+		packs:          make(map[string]bool),
+	}
+}
+
+// buildRegistry assembles a registry from the registered unit packs and
+// the enabled optional packs, then layers the given unit definition files
+// and overlays on top of it.
+func buildRegistry(packs []string, paths []string, overlays ...UnitFile) (*registry, error) {
+	reg := newRegistry()
+	for _, p := range registeredProviders() {
+		if err := reg.applyPack(p.Provide()); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range packs {
+		p, ok := optionalProvider(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown unit pack %s (available: %s)", name, strings.Join(OptionalPacks(), ", "))
+		}
+		if err := reg.applyPack(p.Provide()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, path := range paths {
+		file, err := loadUnitFile(path)
+		if err != nil {
+			return nil, err
+		}
+		reg.applyFile(file)
+	}
+	for _, overlay := range overlays {
+		reg.applyFile(overlay)
+	}
+
+	for alias, symbol := range reg.aliases {
+		if _, ok := reg.units[symbol]; !ok {
+			return nil, fmt.Errorf("alias %s refers to unknown unit %s", alias, symbol)
+		}
+	}
+	reg.buildIndexes()
+	if err := reg.validate(); err != nil {
+		return nil, err
+	}
+	reg.buildHandles()
+	reg.version = reg.computeVersion()
+	return reg, nil
+}
+
+// buildIndexes precomputes the per-dimension views served on every request.
+func (r *registry) buildIndexes() {
+	r.byDimension = make(map[string]map[string]Unit)
+	for symbol, unit := range r.units {
+		units, ok := r.byDimension[unit.Dimension]
+		if !ok {
+			units = make(map[string]Unit)
+			r.byDimension[unit.Dimension] = units
+		}
+		units[symbol] = unit
+	}
+
+	r.dimensions = make([]string, 0, len(r.byDimension))
+	r.dimensionLabels = make(map[string]string, len(r.byDimension))
+	for dim := range r.byDimension {
+		r.dimensions = append(r.dimensions, dim)
+		if name, ok := r.dimensionNames[dim]; ok {
+			r.dimensionLabels[dim] = name
+		} else {
+			r.dimensionLabels[dim] = defaultDimensionName(dim)
+		}
+	}
+	sort.Strings(r.dimensions)
+
+	r.bases = make(map[string]string, len(r.dimensions))
+	for _, dim := range r.dimensions {
+		if symbol, ok := r.baseUnits[dim]; ok {
+			r.bases[dim] = symbol
+		} else if candidates := r.baseCandidates(dim); len(candidates) == 1 {
+			r.bases[dim] = candidates[0]
+		}
+	}
+}
+
+// isBase reports whether unit can be the base unit of its dimension: factor
+// 1, no offset and no custom conversion.
+func isBase(unit Unit) bool {
+	return unit.Factor == 1 && unit.Offset == 0 && unit.Conversion == nil
+}
+
+// baseCandidates returns the units of a dimension that could be its base
+// unit, sorted. Dimensions with several must declare theirs.
+func (r *registry) baseCandidates(dimension string) []string {
+	var candidates []string
+	for _, symbol := range sortedKeys(r.byDimension[dimension]) {
+		if isBase(r.units[symbol]) {
+			candidates = append(candidates, symbol)
+		}
+	}
+	return candidates
+}
+
+// sortedKeys returns the keys of a unit map in order.
+func sortedKeys(units map[string]Unit) []string {
+	keys := make([]string, 0, len(units))
+	for symbol := range units {
+		keys = append(keys, symbol)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// lookup finds a unit by symbol or alias and returns its canonical symbol.
+func (r *registry) lookup(symbol string) (string, Unit, bool) {
+	if unit, ok := r.units[symbol]; ok {
+		return symbol, unit, true
+	}
+	if target, ok := r.aliases[symbol]; ok {
+		unit, ok := r.units[target]
+		return target, unit, ok
+	}
+	return "", Unit{}, false
+}
+
+// baseUnit returns the symbol of a dimension's base unit: the one its pack
+// or unit file declares, or else its only unit with factor 1 and no
+// offset. Registry validation ensures there is one.
+func (r *registry) baseUnit(dimension string) (string, bool) {
+	symbol, ok := r.bases[dimension]
+	return symbol, ok
+}
+
+// UnitFile is the on-disk format of a unit definition file. It doubles as
+// the catalog import/export format.
+type UnitFile struct {
+	Version    int               `json:"version,omitempty"`
+	Units      map[string]Unit   `json:"units"`
+	Aliases    map[string]string `json:"aliases,omitempty"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	BaseUnits  map[string]string `json:"baseUnits,omitempty"`
+}
+
+// applyFile merges a unit definition file into the registry. Unlike packs,
+// files may override existing definitions.
+func (r *registry) applyFile(file UnitFile) {
+	for symbol, unit := range file.Units {
+		r.units[symbol] = unit
+	}
+	for alias, symbol := range file.Aliases {
+		r.aliases[alias] = symbol
+	}
+	for dim, name := range file.Dimensions {
+		r.dimensionNames[dim] = name
+	}
+	for dim, symbol := range file.BaseUnits {
+		r.baseUnits[dim] = symbol
+	}
+}
+
+// loadUnitFile reads and validates a unit definition file.
+func loadUnitFile(path string) (UnitFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return UnitFile{}, err
+	}
+
+	var file UnitFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return UnitFile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.validate(); err != nil {
+		return UnitFile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// validate checks the unit definitions of a file on their own. References
+// to units defined elsewhere are checked when the registry is built.
+func (f UnitFile) validate() error {
+	if f.Version > catalogVersion {
+		return fmt.Errorf("unsupported catalog version %d", f.Version)
+	}
+	for symbol, unit := range f.Units {
+		if symbol == "" {
+			return fmt.Errorf("unit with empty symbol")
+		}
+		if unit.Dimension == "" {
+			return fmt.Errorf("unit %s has no dimension", symbol)
+		}
+		if unit.Factor == 0 {
+			return fmt.Errorf("unit %s has a zero factor", symbol)
+		}
+	}
+	for alias, symbol := range f.Aliases {
+		if alias == "" || symbol == "" {
+			return fmt.Errorf("alias entries must not be empty")
+		}
+	}
+	for dim, symbol := range f.BaseUnits {
+		if dim == "" || symbol == "" {
+			return fmt.Errorf("base unit entries must not be empty")
+		}
+	}
+	return nil
+}
+
+// merge layers other over f and returns the result without modifying either.
+func (f UnitFile) merge(other UnitFile) UnitFile {
+	merged := UnitFile{
+		Units:      make(map[string]Unit),
+		Aliases:    make(map[string]string),
+		Dimensions: make(map[string]string),
+		BaseUnits:  make(map[string]string),
+	}
+	for _, src := range []UnitFile{f, other} {
+		for symbol, unit := range src.Units {
+			merged.Units[symbol] = unit
+		}
+		for alias, symbol := range src.Aliases {
+			merged.Aliases[alias] = symbol
+		}
+		for dim, name := range src.Dimensions {
+			merged.Dimensions[dim] = name
+		}
+		for dim, symbol := range src.BaseUnits {
+			merged.BaseUnits[dim] = symbol
+		}
+	}
+	return merged
+}
+
+// RegistryDiff lists the unit symbols affected by a registry swap.
+type RegistryDiff struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// Empty reports whether the diff contains no changes.
+func (d RegistryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0
+}
+
+// sameUnit reports whether two unit definitions are equivalent. Custom
+// conversions are compared by type only since they are compiled in.
+func sameUnit(a, b Unit) bool {
+	convA, convB := a.Conversion, b.Conversion
+	a.Conversion, b.Conversion = nil, nil
+	return a == b && reflect.TypeOf(convA) == reflect.TypeOf(convB)
+}
+
+// diffRegistries reports how next differs from prev.
+func diffRegistries(prev, next *registry) RegistryDiff {
+	diff := RegistryDiff{Added: []string{}, Changed: []string{}, Removed: []string{}}
+	for symbol, unit := range next.units {
+		old, ok := prev.units[symbol]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, symbol)
+		case !sameUnit(old, unit):
+			diff.Changed = append(diff.Changed, symbol)
+		}
+	}
+	for symbol := range prev.units {
+		if _, ok := next.units[symbol]; !ok {
+			diff.Removed = append(diff.Removed, symbol)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	sort.Strings(diff.Removed)
+	return diff
+}
+
+// snapshot returns the registry currently in use.
+func (uc *UnitConverter) snapshot() *registry {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.reg
+}
+
+// Snapshot is a read-only view of a converter's registry at one point in
+// time. Later reloads and imports don't affect it, so everything read from
+// one snapshot is consistent. The maps and slices it returns are shared
+// with the registry and must not be modified.
+type Snapshot struct {
+	reg *registry
+}
+
+// Snapshot returns a view of the registry currently in use.
+func (uc *UnitConverter) Snapshot() Snapshot {
+	return Snapshot{reg: uc.snapshot()}
+}
+
+// Units returns every unit keyed by symbol.
+func (s Snapshot) Units() map[string]Unit {
+	return s.reg.units
+}
+
+// Aliases returns the alternative spellings mapped to unit symbols.
+func (s Snapshot) Aliases() map[string]string {
+	return s.reg.aliases
+}
+
+// Dimensions returns the dimension keys, sorted.
+func (s Snapshot) Dimensions() []string {
+	return s.reg.dimensions
+}
+
+// UnitsByDimension returns the units of every dimension keyed by symbol.
+func (s Snapshot) UnitsByDimension() map[string]map[string]Unit {
+	return s.reg.byDimension
+}
+
+// DimensionNames returns the display name of every dimension.
+func (s Snapshot) DimensionNames() map[string]string {
+	return s.reg.dimensionLabels
+}
+
+// Lookup finds a unit by symbol or alias and returns its canonical symbol.
+func (s Snapshot) Lookup(symbol string) (string, Unit, bool) {
+	return s.reg.lookup(symbol)
+}
+
+// BaseUnit returns the symbol of a dimension's base unit.
+func (s Snapshot) BaseUnit(dimension string) (string, bool) {
+	return s.reg.baseUnit(dimension)
+}
+
+// OnChange registers fn to be called after every registry swap that
+// added, changed or removed units.
+func (uc *UnitConverter) OnChange(fn func(RegistryDiff)) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.listeners = append(uc.listeners, fn)
+}
+
+// SetUnitFiles sets the unit definition files applied by Reload.
+func (uc *UnitConverter) SetUnitFiles(paths []string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.unitFiles = append([]string(nil), paths...)
+}
+
+// SetUnitPacks sets the optional unit packs applied by Reload.
+func (uc *UnitConverter) SetUnitPacks(names []string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.unitPacks = append([]string(nil), names...)
+}
+
+// build assembles a registry from the enabled unit packs, the unit files
+// and the given imported catalogs. A namespace applies its parent's files
+// and imports first, then its own.
+func (uc *UnitConverter) build(imported UnitFile) (*registry, error) {
+	uc.mu.RLock()
+	packs, paths, parent, namespaceFiles := uc.unitPacks, uc.unitFiles, uc.parent, uc.namespaceFiles
+	uc.mu.RUnlock()
+	if parent == nil {
+		return buildRegistry(packs, paths, imported)
+	}
+
+	parent.mu.RLock()
+	packs, paths, overlays := parent.unitPacks, parent.unitFiles, []UnitFile{parent.imported}
+	parent.mu.RUnlock()
+	for _, path := range namespaceFiles {
+		file, err := loadUnitFile(path)
+		if err != nil {
+			return nil, err
+		}
+		overlays = append(overlays, file)
+	}
+	return buildRegistry(packs, paths, append(overlays, imported)...)
+}
+
+// Reload rebuilds the registry from the registered and enabled unit packs,
+// the configured unit files and imported catalogs, then swaps it in. On
+// error the current registry is kept.
+func (uc *UnitConverter) Reload() (RegistryDiff, error) {
+	uc.updateMu.Lock()
+	defer uc.updateMu.Unlock()
+
+	uc.mu.RLock()
+	imported := uc.imported
+	uc.mu.RUnlock()
+
+	next, err := uc.build(imported)
+	if err != nil {
+		return RegistryDiff{}, err
+	}
+	return uc.swap(next), nil
+}
+
+// Import layers a catalog over the registry. Imported definitions override
+// built-in and file units and survive reloads. With dryRun set, the catalog
+// is validated and the resulting diff returned without applying it.
+func (uc *UnitConverter) Import(catalog UnitFile, dryRun bool) (RegistryDiff, error) {
+	if err := catalog.validate(); err != nil {
+		return RegistryDiff{}, err
+	}
+
+	uc.updateMu.Lock()
+	defer uc.updateMu.Unlock()
+
+	uc.mu.RLock()
+	imported, current := uc.imported, uc.reg
+	uc.mu.RUnlock()
+
+	merged := imported.merge(catalog)
+	next, err := uc.build(merged)
+	if err != nil {
+		return RegistryDiff{}, err
+	}
+	if dryRun {
+		return diffRegistries(current, next), nil
+	}
+
+	uc.mu.Lock()
+	uc.imported = merged
+	uc.mu.Unlock()
+	return uc.swap(next), nil
+}
+
+// swap installs next as the current registry and notifies listeners.
+func (uc *UnitConverter) swap(next *registry) RegistryDiff {
+	uc.mu.Lock()
+	diff := diffRegistries(uc.reg, next)
+	uc.reg = next
+	listeners := uc.listeners
+	uc.mu.Unlock()
+
+	if !diff.Empty() {
+		for _, fn := range listeners {
+			fn(diff)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,141 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// UnitMatch is a unit returned by SearchUnits.
+type UnitMatch struct {
+	Symbol    string `json:"symbol"`
+	Name      string `json:"name"`
+	Dimension string `json:"dimension"`
+}
+
+// SearchUnits finds units whose symbol, name or aliases contain query,
+// ignoring case. An empty dimension searches every dimension. Exact symbol
+// matches come first, then results are ordered by symbol.
+func (uc *UnitConverter) SearchUnits(query, dimension string) []UnitMatch {
+	reg := uc.snapshot()
+	q := strings.ToLower(strings.TrimSpace(query))
+
+	aliasesOf := make(map[string][]string)
+	for alias, symbol := range reg.aliases {
+		aliasesOf[symbol] = append(aliasesOf[symbol], alias)
+	}
+
+	matches := []UnitMatch{}
+	for symbol, unit := range reg.units {
+		if dimension != "" && unit.Dimension != dimension {
+			continue
+		}
+		found := strings.Contains(strings.ToLower(symbol), q) ||
+			strings.Contains(strings.ToLower(unit.Name), q)
+		for _, alias := range aliasesOf[symbol] {
+			found = found || strings.Contains(strings.ToLower(alias), q)
+		}
+		if found {
+			matches = append(matches, UnitMatch{Symbol: symbol, Name: unit.Name, Dimension: unit.Dimension})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		exactI := strings.ToLower(matches[i].Symbol) == q
+		exactJ := strings.ToLower(matches[j].Symbol) == q
+		if exactI != exactJ {
+			return exactI
+		}
+		return matches[i].Symbol < matches[j].Symbol
+	})
+	return matches
+}
+
+// maxSuggestions is the number of suggestions offered for an unknown unit.
+const maxSuggestions = 3
+
+// UnknownUnitError reports a unit that is neither a known symbol nor an alias.
+type UnknownUnitError struct {
+	Symbol      string
+	Role        string   // "source" or "target"
+	Suggestions []string // Closest known symbols, best first
+}
+
+func (e *UnknownUnitError) Error() string {
+	msg := fmt.Sprintf("invalid %s unit: %s", e.Role, e.Symbol)
+	if len(e.Suggestions) > 0 {
+		msg += " (did you mean " + strings.Join(e.Suggestions, ", ") + "?)"
+	}
+	return msg
+}
+
+// Suggest returns the symbols of the units whose symbol, name or aliases
+// are closest to query by edit distance, ignoring case. Only reasonably
+// close matches are returned, so the result may be empty.
+func (uc *UnitConverter) Suggest(query string) []string {
+	reg := uc.snapshot()
+	q := strings.ToLower(strings.TrimSpace(query))
+	if q == "" {
+		return nil
+	}
+
+	// Allow roughly one typo per four characters
+	maxDist := max(1, utf8.RuneCountInString(q)/4)
+
+	best := make(map[string]int)
+	consider := func(symbol, candidate string) {
+		d := levenshtein(q, strings.ToLower(candidate))
+		if d > maxDist {
+			return
+		}
+		if prev, ok := best[symbol]; !ok || d < prev {
+			best[symbol] = d
+		}
+	}
+	for symbol, unit := range reg.units {
+		consider(symbol, symbol)
+		consider(symbol, unit.Name)
+	}
+	for alias, symbol := range reg.aliases {
+		consider(symbol, alias)
+	}
+
+	suggestions := make([]string, 0, len(best))
+	for symbol := range best {
+		suggestions = append(suggestions, symbol)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if best[a] != best[b] {
+			return best[a] < best[b]
+		}
+		return a < b
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// levenshtein returns the edit distance between a and b, counted in runes.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,145 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Decibel references. Sound pressure levels are relative to 20 µPa, the
+// nominal threshold of human hearing, unless configured otherwise.
+const (
+	DefaultReferencePressure = 20e-6 // Pa
+	referenceIntensity       = 1e-12 // W/m²
+)
+
+// decibelConversion is a logarithmic unit: level = multiplier × log10(x /
+// reference). The multiplier is 20 for field quantities such as pressure
+// and 10 for power quantities such as intensity.
+type decibelConversion struct {
+	reference  float64
+	multiplier float64
+}
+
+func (d decibelConversion) ToBase(level float64) float64 {
+	return d.reference * math.Pow(10, level/d.multiplier)
+}
+
+func (d decibelConversion) FromBase(value float64) float64 {
+	return d.multiplier * math.Log10(value/d.reference)
+}
+
+// soundPack adds sound pressure and intensity levels in decibels, plus the
+// small pressure units that make 0 dB SPL readable.
+func soundPack() UnitPack {
+	return UnitPack{
+		Name: "sound",
+		Units: withMetadata(map[string]Unit{
+			// Sound pressure, in the pressure dimension (base = pascal)
+			"dB SPL": {Dimension: "pressure", Name: "Decibel (sound pressure level)", Conversion: decibelConversion{DefaultReferencePressure, 20}},
+			"mPa":    {Factor: 1e-3, Dimension: "pressure", Name: "Millipascal"},
+			"µPa":    {Factor: 1e-6, Dimension: "pressure", Name: "Micropascal"},
+
+			// Sound intensity units (base = watt per square meter)
+			"W/m²":   {Factor: 1, Dimension: "sound_intensity", Name: "Watt per square meter"},
+			"dB SIL": {Dimension: "sound_intensity", Name: "Decibel (sound intensity level)", Conversion: decibelConversion{referenceIntensity, 10}},
+		}, map[string]UnitMetadata{
+			"dB SPL": meta(SystemNonSI, sourceSI, "Sound pressure level: 20·log10(p / p₀) with p₀ = 20 µPa by default.", "Noise measurements, hearing protection, speaker specs", "Sound_pressure#Sound_pressure_level"),
+			"mPa":    meta(SystemSI, sourceSI, "One thousandth of a pascal.", "Quiet sound pressures", "Pascal_(unit)"),
+			"µPa":    meta(SystemSI, sourceSI, "One millionth of a pascal.", "Threshold of hearing (20 µPa), underwater acoustics", "Pascal_(unit)"),
+			"W/m²":   meta(SystemSI, sourceSI, "SI unit of intensity: power per unit area.", "Acoustic intensity, solar irradiance", "Sound_intensity"),
+			"dB SIL": meta(SystemNonSI, sourceSI, "Sound intensity level: 10·log10(I / I₀) with I₀ = 10⁻¹² W/m².", "Acoustics, noise exposure", "Sound_intensity#Sound_intensity_level"),
+		}),
+		Aliases: map[string]string{
+			"dBSPL": "dB SPL", "dB(SPL)": "dB SPL", "dB_SPL": "dB SPL", "spl": "dB SPL",
+			"uPa": "µPa", "μPa": "µPa",
+			"W/m2":  "W/m²",
+			"dBSIL": "dB SIL", "dB(SIL)": "dB SIL", "dB_SIL": "dB SIL",
+		},
+		Dimensions: map[string]string{"sound_intensity": "Sound Intensity"},
+		BaseUnits:  map[string]string{"sound_intensity": "W/m²"},
+	}
+}
+
+func init() {
+	RegisterProvider(soundPack())
+}
+
+// SetReferencePressure sets the reference pressure of dB SPL used by
+// ConvertWithReference when a request doesn't give one. Zero restores
+// DefaultReferencePressure.
+func (uc *UnitConverter) SetReferencePressure(pascals float64) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.referencePressure = pascals
+}
+
+// ReferencePressure returns the configured dB SPL reference in pascals.
+func (uc *UnitConverter) ReferencePressure() float64 {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if uc.referencePressure == 0 {
+		return DefaultReferencePressure
+	}
+	return uc.referencePressure
+}
+
+// ParseReferencePressure reads a reference pressure in pascals. An empty
+// value means the converter's configured reference.
+func (uc *UnitConverter) ParseReferencePressure(value string) (float64, error) {
+	if value == "" {
+		return uc.ReferencePressure(), nil
+	}
+	p, err := ParseNumber(value, false)
+	if err != nil || p <= 0 {
+		return 0, fmt.Errorf("invalid reference %q: must be a positive pressure in Pa", value)
+	}
+	return p, nil
+}
+
+// IsDecibelLevel reports whether a unit is a sound pressure or intensity
+// level in decibels.
+func (uc *UnitConverter) IsDecibelLevel(unit string) bool {
+	ref := uc.Resolve(unit)
+	if !ref.Valid() {
+		return false
+	}
+	_, ok := ref.Unit().Conversion.(decibelConversion)
+	return ok
+}
+
+// IsSoundPressureLevel reports whether a unit is measured relative to the
+// reference pressure.
+func (uc *UnitConverter) IsSoundPressureLevel(unit string) bool {
+	ref := uc.Resolve(unit)
+	if !ref.Valid() {
+		return false
+	}
+	d, ok := ref.Unit().Conversion.(decibelConversion)
+	return ok && d.reference == DefaultReferencePressure
+}
+
+// ConvertWithReference converts like Convert, measuring sound pressure
+// levels against reference (in Pa) instead of 20 µPa.
+func (uc *UnitConverter) ConvertWithReference(ctx context.Context, value float64, from, to string, reference float64) (float64, error) {
+	if reference == DefaultReferencePressure || (!uc.IsSoundPressureLevel(from) && !uc.IsSoundPressureLevel(to)) {
+		return uc.ConvertContext(ctx, value, from, to)
+	}
+	// Check symbols and dimensions the usual way first
+	if _, err := uc.ConvertContext(ctx, value, from, to); err != nil && !errors.Is(err, ErrOutOfRange) {
+		return 0, err
+	}
+
+	fromUnit, toUnit := uc.Resolve(from).Unit(), uc.Resolve(to).Unit()
+	for _, u := range []*Unit{&fromUnit, &toUnit} {
+		if d, ok := u.Conversion.(decibelConversion); ok && d.reference == DefaultReferencePressure {
+			u.Conversion = decibelConversion{reference, d.multiplier}
+		}
+	}
+	result := toUnit.FromBase(fromUnit.ToBase(value))
+	if math.IsNaN(result) || math.IsInf(result, 0) {
+		return 0, ErrOutOfRange
+	}
+	return roundResult(result), nil
+}
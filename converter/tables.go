@@ -0,0 +1,207 @@
+package converter
+
+import "math"
+
+// tableMode selects how a tableConversion treats values between its points.
+type tableMode int
+
+const (
+	// tableInterpolate is piecewise linear between points and rejects
+	// values outside the table.
+	tableInterpolate tableMode = iota
+	// tableStep treats each point's base value as the lower bound of its
+	// step, like Beaufort forces. Scale values convert to the middle of
+	// their step.
+	tableStep
+	// tableNearest snaps to the closest point, like numbered drill sizes.
+	tableNearest
+)
+
+// tablePoint maps one value of a scale to the base unit of its dimension.
+type tablePoint struct {
+	Scale float64
+	Base  float64
+}
+
+// tableConversion converts units defined by a lookup table rather than a
+// factor, for discrete or non-linear scales. Points are sorted by scale;
+// base values must be monotonic, increasing or decreasing.
+type tableConversion struct {
+	mode   tableMode
+	points []tablePoint
+}
+
+func (t tableConversion) ToBase(value float64) float64 {
+	p := t.points
+	switch t.mode {
+	case tableInterpolate:
+		return interpolate(p, value, func(pt tablePoint) float64 { return pt.Scale }, func(pt tablePoint) float64 { return pt.Base })
+	case tableStep:
+		i := t.nearestScale(value)
+		if i < 0 {
+			return math.NaN()
+		}
+		if i == len(p)-1 {
+			return p[i].Base // The last step is open-ended
+		}
+		return (p[i].Base + p[i+1].Base) / 2
+	default:
+		i := t.nearestScale(value)
+		if i < 0 {
+			return math.NaN()
+		}
+		return p[i].Base
+	}
+}
+
+func (t tableConversion) FromBase(value float64) float64 {
+	p := t.points
+	switch t.mode {
+	case tableInterpolate:
+		return interpolate(p, value, func(pt tablePoint) float64 { return pt.Base }, func(pt tablePoint) float64 { return pt.Scale })
+	case tableStep:
+		// Steps are sorted by increasing lower bound
+		if value < p[0].Base {
+			return math.NaN()
+		}
+		step := p[0].Scale
+		for _, pt := range p {
+			if value >= pt.Base {
+				step = pt.Scale
+			}
+		}
+		return step
+	default:
+		best := 0
+		for i, pt := range p {
+			if math.Abs(value-pt.Base) < math.Abs(value-p[best].Base) {
+				best = i
+			}
+		}
+		// Allow half a gap beyond the ends of the table
+		neighbor := best + 1
+		if best == len(p)-1 {
+			neighbor = best - 1
+		}
+		if (best == 0 || best == len(p)-1) && math.Abs(value-p[best].Base) > math.Abs(p[best].Base-p[neighbor].Base)/2 {
+			return math.NaN()
+		}
+		return p[best].Scale
+	}
+}
+
+// RoundTripSamples returns the scale values of the table. Discrete tables
+// only round-trip at their points, so registry validation checks those.
+func (t tableConversion) RoundTripSamples() []float64 {
+	samples := make([]float64, len(t.points))
+	for i, pt := range t.points {
+		samples[i] = pt.Scale
+	}
+	return samples
+}
+
+// nearestScale returns the index of the point whose scale value is closest
+// to value, or -1 when value is more than half a step outside the table.
+func (t tableConversion) nearestScale(value float64) int {
+	p := t.points
+	if value < p[0].Scale-0.5 || value > p[len(p)-1].Scale+0.5 {
+		return -1
+	}
+	best := 0
+	for i, pt := range p {
+		if math.Abs(value-pt.Scale) < math.Abs(value-p[best].Scale) {
+			best = i
+		}
+	}
+	return best
+}
+
+// interpolate maps x to y linearly between the two surrounding points. x
+// must be monotonic over the points; values outside give NaN.
+func interpolate(points []tablePoint, value float64, x, y func(tablePoint) float64) float64 {
+	for i := 0; i+1 < len(points); i++ {
+		x0, x1 := x(points[i]), x(points[i+1])
+		if (value >= x0 && value <= x1) || (value <= x0 && value >= x1) {
+			if x0 == x1 {
+				return y(points[i])
+			}
+			return y(points[i]) + (value-x0)*(y(points[i+1])-y(points[i]))/(x1-x0)
+		}
+	}
+	return math.NaN()
+}
+
+// beaufortScale holds the lower bound of each Beaufort force in m/s.
+var beaufortScale = tableConversion{mode: tableStep, points: []tablePoint{
+	{0, 0}, {1, 0.5}, {2, 1.6}, {3, 3.4}, {4, 5.5}, {5, 8.0}, {6, 10.8},
+	{7, 13.9}, {8, 17.2}, {9, 20.8}, {10, 24.5}, {11, 28.5}, {12, 32.7},
+}}
+
+// awgScale gives wire diameters in meters for gauges 0000 (-3) to 40:
+// d = 0.127 mm × 92^((36 − n) / 39).
+var awgScale = func() tableConversion {
+	t := tableConversion{mode: tableInterpolate}
+	for n := -3; n <= 40; n++ {
+		t.points = append(t.points, tablePoint{float64(n), 0.000127 * math.Pow(92, float64(36-n)/39)})
+	}
+	return t
+}()
+
+// usRingScale gives inner diameters in meters of US ring sizes 3 to 13
+// (ISO 8653 tables, rounded to 0.1 mm).
+var usRingScale = tableConversion{mode: tableInterpolate, points: []tablePoint{
+	{3, 0.0141}, {4, 0.0149}, {5, 0.0157}, {6, 0.0165}, {7, 0.0173}, {8, 0.0181},
+	{9, 0.0189}, {10, 0.0198}, {11, 0.0206}, {12, 0.0214}, {13, 0.0222},
+}}
+
+// numberDrillScale gives the diameters of number drills #1 to #80, in
+// inches as published, converted to meters.
+var numberDrillScale = func() tableConversion {
+	inches := []float64{
+		.2280, .2210, .2130, .2090, .2055, .2040, .2010, .1990, .1960, .1935,
+		.1910, .1890, .1850, .1820, .1800, .1770, .1730, .1695, .1660, .1610,
+		.1590, .1570, .1540, .1520, .1495, .1470, .1440, .1405, .1360, .1285,
+		.1200, .1160, .1130, .1110, .1100, .1065, .1040, .1015, .0995, .0980,
+		.0960, .0935, .0890, .0860, .0820, .0810, .0785, .0760, .0730, .0700,
+		.0670, .0635, .0595, .0550, .0520, .0465, .0430, .0420, .0410, .0400,
+		.0390, .0380, .0370, .0360, .0350, .0330, .0320, .0310, .0292, .0280,
+		.0260, .0250, .0240, .0225, .0210, .0200, .0180, .0160, .0145, .0135,
+	}
+	t := tableConversion{mode: tableNearest}
+	for i, d := range inches {
+		t.points = append(t.points, tablePoint{float64(i + 1), d * 0.0254})
+	}
+	return t
+}()
+
+// tablesPack adds units defined by lookup tables. Their values are scale
+// numbers (force 4, gauge 12, size 7) rather than quantities.
+func tablesPack() UnitPack {
+	return UnitPack{
+		Name: "tables",
+		Units: withMetadata(map[string]Unit{
+			"Bft":     {Dimension: "speed", Name: "Beaufort force", Conversion: beaufortScale},
+			"AWG":     {Dimension: "length", Name: "American Wire Gauge (diameter)", Conversion: awgScale},
+			"ring_us": {Dimension: "length", Name: "US ring size (inner diameter)", Conversion: usRingScale},
+			"ring_eu": {Factor: 0.001 / math.Pi, Dimension: "length", Name: "EU ring size (inner circumference in mm)"},
+			"drill#":  {Dimension: "length", Name: "Number drill size (diameter)", Conversion: numberDrillScale},
+		}, map[string]UnitMetadata{
+			"Bft":     meta(SystemNonSI, "WMO Manual on Codes (WMO-No. 306)", "Empirical wind force scale from 0 (calm) to 12 (hurricane). Speeds convert to the force whose range contains them; forces convert to the middle of their range.", "Marine and weather forecasts", "Beaufort_scale"),
+			"AWG":     meta(SystemUSCustomary, "ASTM B258", "Wire gauge where diameter shrinks geometrically as the number grows; 0000 is -3, 000 is -2, 00 is -1. Fractional gauges are interpolated.", "Electrical wiring in North America", "American_wire_gauge"),
+			"ring_us": meta(SystemUSCustomary, "ISO 8653", "US and Canadian ring size, interpolated between sizes 3 and 13.", "Jewelry", "Ring_size"),
+			"ring_eu": meta(SystemMetric, "ISO 8653", "European ring size: the inner circumference in millimetres.", "Jewelry", "Ring_size"),
+			"drill#":  meta(SystemUSCustomary, "ANSI/ASME B94.11M", "Number drill sizes #1 (0.228 in) to #80 (0.0135 in). Diameters convert to the nearest number.", "Machining, tapping, model making", "Drill_bit_sizes#Number_and_letter_gauge_drill_bits"),
+		}),
+		Aliases: map[string]string{
+			"beaufort": "Bft", "bft": "Bft",
+			"awg": "AWG", "gauge": "AWG",
+			"ring": "ring_us", "ring size": "ring_us", "ring_size": "ring_us",
+			"ring size eu": "ring_eu",
+			"drill":        "drill#", "drill_no": "drill#", "number drill": "drill#",
+		},
+	}
+}
+
+func init() {
+	RegisterProvider(tablesPack())
+}
@@ -0,0 +1,104 @@
+package converter
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTableConversion(t *testing.T) {
+	inch := 0.0254
+	tests := []struct {
+		name  string
+		table tableConversion
+		value float64
+		base  bool // Convert value from the base unit rather than to it
+		want  float64
+	}{
+		// Interpolated tables: exact on points, linear between them
+		{"ring size on a point", usRingScale, 7, false, 0.0173},
+		{"ring size between points", usRingScale, 7.5, false, 0.0177},
+		{"ring diameter between points", usRingScale, 0.0177, true, 7.5},
+		{"ring diameter on a point", usRingScale, 0.0222, true, 13},
+		{"ring size below the table", usRingScale, 2, false, math.NaN()},
+		{"ring size above the table", usRingScale, 14, false, math.NaN()},
+		{"ring diameter outside the table", usRingScale, 0.01, true, math.NaN()},
+		{"gauge on a point", awgScale, 36, false, 0.000127},
+		{"decreasing diameters", awgScale, 0.000127, true, 36},
+
+		// Step tables: scale values give the middle of their step
+		{"force in the middle of its step", beaufortScale, 4, false, (5.5 + 8.0) / 2},
+		{"last force is open-ended", beaufortScale, 12, false, 32.7},
+		{"fractional force rounds to a step", beaufortScale, 4.4, false, (5.5 + 8.0) / 2},
+		{"force above the scale", beaufortScale, 13, false, math.NaN()},
+		{"force below the scale", beaufortScale, -1, false, math.NaN()},
+		{"speed on a lower bound", beaufortScale, 5.5, true, 4},
+		{"speed just below a bound", beaufortScale, 5.4, true, 3},
+		{"speed beyond the last bound", beaufortScale, 100, true, 12},
+		{"negative speed", beaufortScale, -1, true, math.NaN()},
+
+		// Nearest tables: snap to the closest point
+		{"drill on a point", numberDrillScale, 1, false, 0.2280 * inch},
+		{"fractional drill snaps", numberDrillScale, 1.4, false, 0.2280 * inch},
+		{"drill below the table", numberDrillScale, 0, false, math.NaN()},
+		{"drill above the table", numberDrillScale, 81, false, math.NaN()},
+		{"diameter on a point", numberDrillScale, 0.0135 * inch, true, 80},
+		{"diameter between points", numberDrillScale, 0.2215 * inch, true, 2},
+		{"diameter far above the table", numberDrillScale, inch, true, math.NaN()},
+		{"diameter far below the table", numberDrillScale, 0, true, math.NaN()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got float64
+			if tt.base {
+				got = tt.table.FromBase(tt.value)
+			} else {
+				got = tt.table.ToBase(tt.value)
+			}
+			if math.IsNaN(tt.want) {
+				if !math.IsNaN(got) {
+					t.Errorf("got %v, want NaN", got)
+				}
+				return
+			}
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertTableUnits(t *testing.T) {
+	uc := NewUnitConverter()
+	tests := []struct {
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{10, "m/s", "Bft", 5},
+		{20, "km/h", "beaufort", 4},
+		{7, "ring_us", "mm", 17.3},
+		{12, "AWG", "mm", 2.052525},
+		{0.1015, "in", "drill#", 38},
+	}
+	for _, tt := range tests {
+		got, err := uc.Convert(tt.value, tt.from, tt.to)
+		if err != nil || math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("Convert(%v, %s, %s) = %v, %v, want %v", tt.value, tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	// Values outside a table have no result
+	for _, tt := range []struct {
+		value    float64
+		from, to string
+	}{
+		{13, "Bft", "m/s"},
+		{2, "ring_us", "mm"},
+		{1, "m", "drill#"},
+	} {
+		if _, err := uc.Convert(tt.value, tt.from, tt.to); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Convert(%v, %s, %s) error = %v, want ErrOutOfRange", tt.value, tt.from, tt.to, err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package converter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// roundTripTolerance is the relative error allowed when a value is
+// converted to the base unit and back.
+const roundTripTolerance = 1e-9
+
+// roundTripSamples are the values checked for every unit, unless its
+// conversion is a roundTripSampler.
+var roundTripSamples = []float64{1, 37.5, 1e6}
+
+// roundTripSampler is implemented by custom conversions that only
+// round-trip for some values, such as discrete scales.
+type roundTripSampler interface {
+	RoundTripSamples() []float64
+}
+
+// RegistryProblem is one inconsistency found in a registry.
+type RegistryProblem struct {
+	Check   string `json:"check"`  // e.g. "alias-conflict" or "round-trip"
+	Symbol  string `json:"symbol"` // Unit symbol, alias or dimension concerned
+	Message string `json:"message"`
+}
+
+// RegistryValidationError reports every problem found by validating a
+// registry, so a broken unit file or pack can be fixed in one go.
+type RegistryValidationError struct {
+	Problems []RegistryProblem
+}
+
+func (e *RegistryValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "unit registry is inconsistent (%d problems):", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  %s: %s", p.Check, p.Message)
+	}
+	return b.String()
+}
+
+// validate checks a registry once packs, files and overlays are applied:
+// aliases that conflict with unit symbols, units with unusable factors or
+// offsets, dimensions without a clear base unit, and conversions that don't
+// round-trip through the base unit. Problems are reported in a stable order.
+func (r *registry) validate() error {
+	var problems []RegistryProblem
+	report := func(check, symbol, format string, args ...interface{}) {
+		problems = append(problems, RegistryProblem{Check: check, Symbol: symbol, Message: fmt.Sprintf(format, args...)})
+	}
+
+	aliases := make([]string, 0, len(r.aliases))
+	for alias := range r.aliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		target := r.aliases[alias]
+		if strings.TrimSpace(alias) != alias || alias == "" {
+			report("alias-conflict", alias, "alias %q (for %s) has surrounding spaces or is empty", alias, target)
+		}
+		if _, ok := r.units[alias]; ok && target != alias {
+			report("alias-conflict", alias, "alias %s for %s is also the symbol of a unit, so it would resolve to a different unit depending on the lookup", alias, target)
+		}
+	}
+
+	for _, symbol := range sortedKeys(r.units) {
+		unit := r.units[symbol]
+		if strings.TrimSpace(symbol) != symbol || symbol == "" {
+			report("symbol", symbol, "unit symbol %q has surrounding spaces or is empty", symbol)
+		}
+		if unit.Dimension == "" {
+			report("dimension", symbol, "unit %s has no dimension", symbol)
+		}
+		if unit.Conversion == nil {
+			switch {
+			case unit.Factor == 0:
+				report("factor", symbol, "unit %s has a zero factor", symbol)
+				continue
+			case math.IsNaN(unit.Factor) || math.IsInf(unit.Factor, 0):
+				report("factor", symbol, "unit %s has a non-finite factor %g", symbol, unit.Factor)
+				continue
+			case math.IsNaN(unit.Offset) || math.IsInf(unit.Offset, 0):
+				report("factor", symbol, "unit %s has a non-finite offset %g", symbol, unit.Offset)
+				continue
+			}
+		}
+
+		samples := roundTripSamples
+		if sampler, ok := unit.Conversion.(roundTripSampler); ok {
+			samples = sampler.RoundTripSamples()
+		}
+		for _, sample := range samples {
+			base := unit.ToBase(sample)
+			if math.IsNaN(base) || math.IsInf(base, 0) {
+				// Outside the domain of a custom conversion
+				continue
+			}
+			back := unit.FromBase(base)
+			if math.Abs(back-sample) > roundTripTolerance*math.Max(math.Abs(sample), 1) {
+				report("round-trip", symbol, "unit %s converts %g to %g in the base unit and back to %g", symbol, sample, base, back)
+				break
+			}
+		}
+	}
+
+	for _, dim := range r.dimensions {
+		symbol, declared := r.baseUnits[dim]
+		if !declared {
+			switch candidates := r.baseCandidates(dim); len(candidates) {
+			case 0:
+				report("base-unit", dim, "dimension %s has no base unit (a unit with factor 1 and no offset)", dim)
+			case 1:
+			default:
+				report("base-unit", dim, "dimension %s has several units with factor 1 (%s); declare its base unit", dim, strings.Join(candidates, ", "))
+			}
+			continue
+		}
+		unit, ok := r.units[symbol]
+		switch {
+		case !ok:
+			report("base-unit", dim, "base unit %s of dimension %s is not defined", symbol, dim)
+		case unit.Dimension != dim:
+			report("base-unit", dim, "base unit %s of dimension %s belongs to dimension %s", symbol, dim, unit.Dimension)
+		case !isBase(unit):
+			report("base-unit", dim, "base unit %s of dimension %s must have factor 1 and no offset", symbol, dim)
+		}
+	}
+
+	if len(problems) > 0 {
+		return &RegistryValidationError{Problems: problems}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package converter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateRegistry(t *testing.T) {
+	tests := []struct {
+		name    string
+		overlay UnitFile
+		check   string // Expected RegistryProblem.Check
+		symbol  string // Expected RegistryProblem.Symbol
+	}{
+		{
+			name:    "alias is also a unit symbol",
+			overlay: UnitFile{Aliases: map[string]string{"kg": "g"}},
+			check:   "alias-conflict", symbol: "kg",
+		},
+		{
+			name:    "alias with spaces",
+			overlay: UnitFile{Aliases: map[string]string{" metre": "m"}},
+			check:   "alias-conflict", symbol: " metre",
+		},
+		{
+			name:    "zero factor",
+			overlay: UnitFile{Units: map[string]Unit{"nil": {Factor: 0, Dimension: "length", Name: "Nothing"}}},
+			check:   "factor", symbol: "nil",
+		},
+		{
+			name:    "unit without dimension",
+			overlay: UnitFile{Units: map[string]Unit{"thing": {Factor: 1, Name: "Thing"}}},
+			check:   "dimension", symbol: "thing",
+		},
+		{
+			name: "conversion that doesn't round-trip",
+			overlay: UnitFile{Units: map[string]Unit{"lossy": {Dimension: "length", Name: "Lossy", Conversion: ConversionFuncs{
+				To:   func(v float64) float64 { return v * 2 },
+				From: func(v float64) float64 { return v },
+			}}}},
+			check: "round-trip", symbol: "lossy",
+		},
+		{
+			name:    "no base unit",
+			overlay: UnitFile{Units: map[string]Unit{"widget": {Factor: 2, Dimension: "widgets", Name: "Widget"}}},
+			check:   "base-unit", symbol: "widgets",
+		},
+		{
+			name: "several base unit candidates",
+			overlay: UnitFile{Units: map[string]Unit{
+				"widget": {Factor: 1, Dimension: "widgets", Name: "Widget"},
+				"gadget": {Factor: 1, Dimension: "widgets", Name: "Gadget"},
+			}},
+			check: "base-unit", symbol: "widgets",
+		},
+		{
+			name: "declared base unit is missing",
+			overlay: UnitFile{
+				Units:     map[string]Unit{"widget": {Factor: 1, Dimension: "widgets", Name: "Widget"}},
+				BaseUnits: map[string]string{"widgets": "gizmo"},
+			},
+			check: "base-unit", symbol: "widgets",
+		},
+		{
+			name:    "declared base unit has an offset",
+			overlay: UnitFile{BaseUnits: map[string]string{"temperature": "C"}},
+			check:   "base-unit", symbol: "temperature",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildRegistry(nil, nil, tt.overlay)
+			var verr *RegistryValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("buildRegistry error = %v, want a RegistryValidationError", err)
+			}
+			for _, p := range verr.Problems {
+				if p.Check == tt.check && p.Symbol == tt.symbol {
+					return
+				}
+			}
+			t.Errorf("problems %+v, want a %s problem for %q", verr.Problems, tt.check, tt.symbol)
+		})
+	}
+}
+
+func TestValidateRegistryBuiltins(t *testing.T) {
+	if _, err := buildRegistry(OptionalPacks(), nil); err != nil {
+		t.Fatalf("built-in units and packs are inconsistent: %v", err)
+	}
+}
+
+func TestBuildRegistryAliasCycle(t *testing.T) {
+	// An alias must name a unit; aliases of aliases, and so cycles, are rejected
+	overlay := UnitFile{Aliases: map[string]string{"foo": "bar", "bar": "foo"}}
+	_, err := buildRegistry(nil, nil, overlay)
+	if err == nil || !strings.Contains(err.Error(), "refers to unknown unit") {
+		t.Errorf("buildRegistry error = %v, want an unknown unit error", err)
+	}
+}
+
+func TestApplyPackDuplicates(t *testing.T) {
+	unit := Unit{Factor: 1, Dimension: "widgets", Name: "Widget"}
+	tests := []struct {
+		name   string
+		second UnitPack
+		want   string
+	}{
+		{"same pack twice", UnitPack{Name: "first"}, "registered twice"},
+		{"duplicate symbol", UnitPack{Name: "second", Units: map[string]Unit{"widget": unit}}, "unit widget is already defined"},
+		{"duplicate alias", UnitPack{Name: "second", Aliases: map[string]string{"widgets": "widget"}}, "alias widgets is already defined"},
+		{"duplicate base unit", UnitPack{Name: "second", BaseUnits: map[string]string{"widgets": "widget"}}, "base unit of widgets is already defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := newRegistry()
+			first := UnitPack{
+				Name:      "first",
+				Units:     map[string]Unit{"widget": unit},
+				Aliases:   map[string]string{"widgets": "widget"},
+				BaseUnits: map[string]string{"widgets": "widget"},
+			}
+			if err := reg.applyPack(first); err != nil {
+				t.Fatal(err)
+			}
+			if err := reg.applyPack(tt.second); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("applyPack error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+// computeVersion hashes the registry contents into a short version string
+// that changes whenever any unit, alias, dimension name or declared base
+// unit changes.
+func (r *registry) computeVersion() string {
+	h := sha256.New()
+
+	symbols := make([]string, 0, len(r.units))
+	for symbol := range r.units {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	for _, symbol := range symbols {
+		u := r.units[symbol]
+		fmt.Fprintf(h, "u\x00%s\x00%s\x00%s\x00%v\x00%v\x00%T\x00%+v\n", symbol, u.Dimension, u.Name, u.Factor, u.Offset, u.Conversion, u.UnitMetadata)
+	}
+
+	for _, m := range []struct {
+		tag  string
+		data map[string]string
+	}{{"a", r.aliases}, {"d", r.dimensionNames}, {"b", r.baseUnits}} {
+		keys := make([]string, 0, len(m.data))
+		for k := range m.data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(h, "%s\x00%s\x00%s\n", m.tag, k, m.data[k])
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Version returns a hash identifying the current unit catalog.
+func (uc *UnitConverter) Version() string {
+	return uc.snapshot().version
+}
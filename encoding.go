@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Data encodings: the same bytes written as plain text, Base64,
+// hexadecimal or URL-encoded (percent-encoded) text. Conversions go through
+// the raw bytes, so binary data survives any chain of encodings that
+// doesn't pass through "text".
+
+// Encoding names a text form of binary data.
+type Encoding string
+
+const (
+	EncodingText      Encoding = "text"
+	EncodingBase64    Encoding = "base64"
+	EncodingBase64URL Encoding = "base64url"
+	EncodingHex       Encoding = "hex"
+	EncodingURL       Encoding = "url"
+)
+
+// encodings lists the supported encodings in display order.
+var encodings = []Encoding{EncodingText, EncodingBase64, EncodingBase64URL, EncodingHex, EncodingURL}
+
+// maxEncodingBytes limits the size of the decoded data.
+const maxEncodingBytes = 256 << 10
+
+// ErrNotText is returned when decoded data can't be shown as text.
+var ErrNotText = errors.New("data is not valid UTF-8 text, choose base64 or hex instead")
+
+// ParseEncoding validates an encoding name.
+func ParseEncoding(name string) (Encoding, error) {
+	e := Encoding(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range encodings {
+		if e == known {
+			return e, nil
+		}
+	}
+	return "", fmt.Errorf("unknown encoding %q: must be one of text, base64, base64url, hex or url", name)
+}
+
+// Decode returns the bytes that input represents in encoding e. Base64 is
+// accepted with or without padding and hex may contain whitespace.
+func (e Encoding) Decode(input string) ([]byte, error) {
+	var data []byte
+	var err error
+	switch e {
+	case EncodingText:
+		data = []byte(input)
+	case EncodingBase64, EncodingBase64URL:
+		enc := base64.StdEncoding
+		if e == EncodingBase64URL {
+			enc = base64.URLEncoding
+		}
+		input = strings.Join(strings.Fields(input), "")
+		data, err = enc.WithPadding(base64.NoPadding).DecodeString(strings.TrimRight(input, "="))
+	case EncodingHex:
+		data, err = hex.DecodeString(strings.Join(strings.Fields(input), ""))
+	case EncodingURL:
+		var decoded string
+		decoded, err = url.QueryUnescape(input)
+		data = []byte(decoded)
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", e)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s input: %v", e, err)
+	}
+	return data, nil
+}
+
+// Encode writes data in encoding e.
+func (e Encoding) Encode(data []byte) (string, error) {
+	switch e {
+	case EncodingText:
+		if !utf8.Valid(data) {
+			return "", ErrNotText
+		}
+		return string(data), nil
+	case EncodingBase64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case EncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(data), nil
+	case EncodingHex:
+		return hex.EncodeToString(data), nil
+	case EncodingURL:
+		return url.QueryEscape(string(data)), nil
+	}
+	return "", fmt.Errorf("unknown encoding %q", e)
+}
+
+// Recode converts input from one encoding to another, returning the
+// number of bytes it represents.
+func Recode(input string, from, to Encoding) (string, int, error) {
+	data, err := from.Decode(input)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(data) > maxEncodingBytes {
+		return "", len(data), fmt.Errorf("data too large: %d bytes (limit %d)", len(data), maxEncodingBytes)
+	}
+	output, err := to.Encode(data)
+	return output, len(data), err
+}
+
+// EncodingResult is the response of the encoding endpoint.
+type EncodingResult struct {
+	Success bool     `json:"success"`
+	Output  string   `json:"output"`
+	From    Encoding `json:"from"`
+	To      Encoding `json:"to"`
+	Bytes   int      `json:"bytes"`
+}
+
+// Handler for the encoding endpoint
+func encodeHandler(w http.ResponseWriter, r *http.Request) {
+	fail := func(status int, message string) {
+		writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+	}
+
+	if err := r.ParseForm(); err != nil {
+		status, message := bodyError(err, "Error parsing form data")
+		fail(status, message)
+		return
+	}
+	from, err := ParseEncoding(formValueOr(r, "from", string(EncodingText)))
+	if err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+	to, err := ParseEncoding(r.FormValue("to"))
+	if err != nil {
+		fail(http.StatusBadRequest, err.Error())
+		return
+	}
+
+	output, size, err := Recode(r.FormValue("input"), from, to)
+	if err != nil {
+		status := http.StatusBadRequest
+		if size > maxEncodingBytes {
+			status = http.StatusRequestEntityTooLarge
+		}
+		fail(status, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, EncodingResult{Success: true, Output: output, From: from, To: to, Bytes: size})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// Cache policies for catalog responses: clients and proxies may reuse them
+// for a minute before revalidating them with the registry ETag. An API key
+// may select a namespace's catalog, so keyed responses are kept out of
+// shared caches, and all of them vary on the key header.
+const (
+	catalogCacheControl      = "public, max-age=60"
+	keyedCatalogCacheControl = "private, max-age=60"
+)
+
+// etagMatches reports whether an If-None-Match header matches etag.
+// Weak validators match their strong counterparts.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// catalogValidators sets the ETag and cache policy of a catalog response.
+func catalogValidators(h http.Header, r *http.Request, etag string) {
+	h.Set("ETag", etag)
+	if r.Header.Get(apiKeyHeader) != "" {
+		h.Set("Cache-Control", keyedCatalogCacheControl)
+	} else {
+		h.Set("Cache-Control", catalogCacheControl)
+	}
+}
+
+// catalogWriter adds the catalog validators to successful responses only,
+// so that errors such as an unknown unit are never cached or revalidated.
+type catalogWriter struct {
+	http.ResponseWriter
+	r           *http.Request
+	etag        string
+	wroteHeader bool
+}
+
+func (w *catalogWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if status == http.StatusOK {
+			catalogValidators(w.Header(), w.r, w.etag)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *catalogWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *catalogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// withCatalogETag tags successful responses with the registry version and
+// answers matching conditional GET requests with 304 Not Modified.
+func withCatalogETag(uc *converter.UnitConverter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + uc.Version() + `"`
+		w.Header().Add("Vary", apiKeyHeader)
+
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatches(inm, etag) &&
+			(r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			catalogValidators(w.Header(), r, etag)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		next.ServeHTTP(&catalogWriter{ResponseWriter: w, r: r, etag: etag}, r)
+	})
+}
+
+// UnitListing describes a unit in the /api/units response.
+type UnitListing struct {
+	Symbol    string   `json:"symbol"`
+	Name      string   `json:"name"`
+	Dimension string   `json:"dimension"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
+
+// Handler for the unit listing endpoint
+func unitsHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reg := uc.Snapshot()
+
+		aliasesOf := make(map[string][]string)
+		for alias, symbol := range reg.Aliases() {
+			aliasesOf[symbol] = append(aliasesOf[symbol], alias)
+		}
+
+		units := make([]UnitListing, 0, len(reg.Units()))
+		for symbol, unit := range reg.Units() {
+			aliases := aliasesOf[symbol]
+			sort.Strings(aliases)
+			units = append(units, UnitListing{
+				Symbol:    symbol,
+				Name:      unit.Name,
+				Dimension: unit.Dimension,
+				Aliases:   aliases,
+			})
+		}
+		sort.Slice(units, func(i, j int) bool {
+			if units[i].Dimension != units[j].Dimension {
+				return units[i].Dimension < units[j].Dimension
+			}
+			return units[i].Symbol < units[j].Symbol
+		})
+
+		writeJSON(w, http.StatusOK, units)
+	}
+}
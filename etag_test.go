@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header, etag string
+		want         bool
+	}{
+		{`"abc"`, `"abc"`, true},
+		{`W/"abc"`, `"abc"`, true},
+		{`"xyz", "abc"`, `"abc"`, true},
+		{`*`, `"abc"`, true},
+		{`"xyz"`, `"abc"`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, tt.etag); got != tt.want {
+			t.Errorf("etagMatches(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
+
+func TestWithCatalogETag(t *testing.T) {
+	uc := converter.NewUnitConverter()
+	handler := withCatalogETag(uc, unitInfoHandler(uc))
+	etag := `"` + uc.Version() + `"`
+	tests := []struct {
+		name, path, ifNoneMatch string
+		status                  int
+		validators              bool // ETag and Cache-Control are set
+	}{
+		{"found", "/unit-info?unit=m", "", http.StatusOK, true},
+		{"not modified", "/unit-info?unit=m", etag, http.StatusNotModified, true},
+		{"stale", "/unit-info?unit=m", `"old"`, http.StatusOK, true},
+		{"unknown unit", "/unit-info?unit=nope", "", http.StatusBadRequest, false},
+		{"missing unit", "/unit-info", "", http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			gotETag, gotCache := rec.Header().Get("ETag"), rec.Header().Get("Cache-Control")
+			if tt.validators && (gotETag != etag || gotCache != catalogCacheControl) {
+				t.Errorf("ETag %q and Cache-Control %q, want %q and %q", gotETag, gotCache, etag, catalogCacheControl)
+			}
+			if !tt.validators && (gotETag != "" || gotCache != "") {
+				t.Errorf("error response has ETag %q and Cache-Control %q", gotETag, gotCache)
+			}
+			if got := rec.Header().Get("Vary"); got != apiKeyHeader {
+				t.Errorf("Vary %q, want %q", got, apiKeyHeader)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/monsieurr/goverter
+
+go 1.23
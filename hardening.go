@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// newServer builds the HTTP server with the configured timeouts and limits.
+func newServer(cfg Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           limitBody(cfg.MaxBodyBytes, handler),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
+
+// limitBody caps request bodies at maxBytes. Requests that announce a
+// larger body are refused up front; the rest fail on read past the limit.
+func limitBody(maxBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if maxBytes > 0 {
+			if r.ContentLength > maxBytes {
+				writeJSON(w, http.StatusRequestEntityTooLarge, map[string]interface{}{
+					"success": false,
+					"error":   fmt.Sprintf("Request body too large (limit %d bytes)", maxBytes),
+				})
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyError maps an error from reading a request body to a status code and
+// message: 413 past the size limit, 408 when the client was too slow to
+// send it, and 400 with fallback otherwise.
+func bodyError(err error, fallback string) (int, string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge, fmt.Sprintf("Request body too large (limit %d bytes)", tooLarge.Limit)
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return http.StatusRequestTimeout, "Timed out reading request body"
+	}
+	return http.StatusBadRequest, fallback
+}
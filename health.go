@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// Health calculators. Measurements are accepted in any unit of the right
+// dimension and converted to the metric units the formulas use.
+
+// Sex selects the formula variant of the BMR and body fat calculators.
+type Sex string
+
+const (
+	SexMale   Sex = "male"
+	SexFemale Sex = "female"
+)
+
+// ParseSex accepts "male"/"female" and their first letters.
+func ParseSex(value string) (Sex, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "male", "m":
+		return SexMale, nil
+	case "female", "f":
+		return SexFemale, nil
+	}
+	return "", fmt.Errorf("invalid sex %q: must be male or female", value)
+}
+
+// activityLevels are the multipliers from BMR to total daily energy
+// expenditure.
+var activityLevels = map[string]float64{
+	"sedentary":   1.2,
+	"light":       1.375,
+	"moderate":    1.55,
+	"active":      1.725,
+	"very_active": 1.9,
+}
+
+// BMI returns the body mass index for a height in metres and a weight in
+// kilograms, with its WHO category.
+func BMI(heightM, weightKg float64) (bmi float64, category string) {
+	bmi = weightKg / (heightM * heightM)
+	switch {
+	case bmi < 18.5:
+		category = "Underweight"
+	case bmi < 25:
+		category = "Normal weight"
+	case bmi < 30:
+		category = "Overweight"
+	case bmi < 35:
+		category = "Obesity class I"
+	case bmi < 40:
+		category = "Obesity class II"
+	default:
+		category = "Obesity class III"
+	}
+	return bmi, category
+}
+
+// BMR returns the basal metabolic rate in kcal per day using the
+// Mifflin-St Jeor equation.
+func BMR(sex Sex, ageYears, heightCm, weightKg float64) float64 {
+	bmr := 10*weightKg + 6.25*heightCm - 5*ageYears
+	if sex == SexMale {
+		return bmr + 5
+	}
+	return bmr - 161
+}
+
+// BodyFat estimates the body fat percentage from circumferences in
+// centimetres using the U.S. Navy method, with its ACE category. hipCm is
+// only used for women.
+func BodyFat(sex Sex, heightCm, neckCm, waistCm, hipCm float64) (percent float64, category string, err error) {
+	var limits [4]float64
+	if sex == SexMale {
+		if waistCm <= neckCm {
+			return 0, "", fmt.Errorf("waist must be larger than neck")
+		}
+		percent = 495/(1.0324-0.19077*math.Log10(waistCm-neckCm)+0.15456*math.Log10(heightCm)) - 450
+		limits = [4]float64{6, 14, 18, 25}
+	} else {
+		if waistCm+hipCm <= neckCm {
+			return 0, "", fmt.Errorf("waist and hip must be larger than neck")
+		}
+		percent = 495/(1.29579-0.35004*math.Log10(waistCm+hipCm-neckCm)+0.22100*math.Log10(heightCm)) - 450
+		limits = [4]float64{14, 21, 25, 32}
+	}
+
+	switch {
+	case percent < limits[0]:
+		category = "Essential fat"
+	case percent < limits[1]:
+		category = "Athletes"
+	case percent < limits[2]:
+		category = "Fitness"
+	case percent < limits[3]:
+		category = "Average"
+	default:
+		category = "Obese"
+	}
+	return percent, category, nil
+}
+
+// parseMeasurement reads a positive measurement and its unit (the field
+// name with a "_unit" suffix, defaultUnit when absent) and converts it to
+// the unit to.
+func parseMeasurement(uc *converter.UnitConverter, r *http.Request, field, defaultUnit, to string) (float64, error) {
+	value, _, err := parseQuantity(r, field, false)
+	if err != nil {
+		return 0, err
+	}
+	if value <= 0 {
+		return 0, fmt.Errorf("%s must be positive", field)
+	}
+	unit := formValueOr(r, field+"_unit", defaultUnit)
+	toRef := uc.Resolve(to)
+	if ref := uc.Resolve(unit); ref.Valid() && ref.Unit().Dimension != toRef.Unit().Dimension {
+		return 0, fmt.Errorf("%s_unit must be a %s unit such as %s", field, strings.ToLower(uc.GetDimensionName(toRef.Unit().Dimension)), defaultUnit)
+	}
+	return uc.ConvertContext(r.Context(), value, unit, to)
+}
+
+// parseAge reads an age in years.
+func parseAge(r *http.Request) (float64, error) {
+	age, _, err := parseQuantity(r, "age", false)
+	if err != nil {
+		return 0, err
+	}
+	if age < 1 || age > 120 {
+		return 0, fmt.Errorf("age must be between 1 and 120 years")
+	}
+	return age, nil
+}
+
+// round1 rounds to one decimal, the precision health figures are quoted in.
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
+
+// BMIResult is the result of the BMI calculator.
+type BMIResult struct {
+	Success  bool    `json:"success"`
+	BMI      float64 `json:"bmi"`
+	Category string  `json:"category"`
+	HeightM  float64 `json:"heightM"`
+	WeightKg float64 `json:"weightKg"`
+}
+
+// Handler for the BMI calculator
+func bmiHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			calcError(w, status, message)
+			return
+		}
+
+		height, err := parseMeasurement(uc, r, "height", "cm", "m")
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		weight, err := parseMeasurement(uc, r, "weight", "kg", "kg")
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		bmi, category := BMI(height, weight)
+		writeJSON(w, http.StatusOK, BMIResult{
+			Success:  true,
+			BMI:      round1(bmi),
+			Category: category,
+			HeightM:  height,
+			WeightKg: weight,
+		})
+	}
+}
+
+// BMRResult is the result of the BMR calculator. TDEE, the total daily
+// energy expenditure, is only set when an activity level is given.
+type BMRResult struct {
+	Success   bool    `json:"success"`
+	BMR       float64 `json:"bmr"`
+	Activity  string  `json:"activity,omitempty"`
+	TDEE      float64 `json:"tdee,omitempty"`
+	Unit      string  `json:"unit"`
+	Formatted string  `json:"formatted"`
+}
+
+// Handler for the BMR calculator. Results are per day, in kcal unless
+// another energy unit is requested with "to".
+func bmrHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			calcError(w, status, message)
+			return
+		}
+
+		sex, err := ParseSex(r.FormValue("sex"))
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		age, err := parseAge(r)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		height, err := parseMeasurement(uc, r, "height", "cm", "cm")
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		weight, err := parseMeasurement(uc, r, "weight", "kg", "kg")
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		activity := r.FormValue("activity")
+		multiplier, ok := activityLevels[activity]
+		if activity != "" && !ok {
+			calcError(w, http.StatusBadRequest, "activity must be one of sedentary, light, moderate, active or very_active")
+			return
+		}
+
+		to := formValueOr(r, "to", "kcal")
+		kcal := BMR(sex, age, height, weight)
+		bmr, err := uc.Convert(kcal, "kcal", to)
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result := BMRResult{Success: true, BMR: math.Round(bmr), Unit: to}
+		result.Formatted = strconv.FormatFloat(result.BMR, 'f', 0, 64) + " " + to + "/day"
+		if ok {
+			result.Activity = activity
+			result.TDEE = math.Round(bmr * multiplier)
+			result.Formatted += ", " + strconv.FormatFloat(result.TDEE, 'f', 0, 64) + " " + to + "/day with " + strings.ReplaceAll(activity, "_", " ") + " activity"
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
+
+// BodyFatResult is the result of the body fat calculator. The masses are
+// only set when a weight is given.
+type BodyFatResult struct {
+	Success  bool    `json:"success"`
+	BodyFat  float64 `json:"bodyFat"`
+	Category string  `json:"category"`
+	FatKg    float64 `json:"fatKg,omitempty"`
+	LeanKg   float64 `json:"leanKg,omitempty"`
+}
+
+// Handler for the body fat calculator
+func bodyFatHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			calcError(w, status, message)
+			return
+		}
+
+		sex, err := ParseSex(r.FormValue("sex"))
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		fields := []string{"height", "neck", "waist"}
+		if sex == SexFemale {
+			fields = append(fields, "hip")
+		}
+		cm := make(map[string]float64, len(fields))
+		for _, field := range fields {
+			if cm[field], err = parseMeasurement(uc, r, field, "cm", "cm"); err != nil {
+				calcError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		percent, category, err := BodyFat(sex, cm["height"], cm["neck"], cm["waist"], cm["hip"])
+		if err != nil {
+			calcError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		result := BodyFatResult{Success: true, BodyFat: round1(percent), Category: category}
+		if r.FormValue("weight") != "" {
+			weight, err := parseMeasurement(uc, r, "weight", "kg", "kg")
+			if err != nil {
+				calcError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			result.FatKg = round1(weight * percent / 100)
+			result.LeanKg = round1(weight - result.FatKg)
+		}
+		writeJSON(w, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Session history limits. Sessions idle for longer than historyTTL are
+// dropped by the history-prune job.
+const (
+	historyCookie     = "goverter_session"
+	historyTTL        = 7 * 24 * time.Hour
+	maxHistoryEntries = 1000
+)
+
+// HistoryEntry is one successful conversion made by a session.
+type HistoryEntry struct {
+	Time      time.Time `json:"time"`
+	Value     float64   `json:"value"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Result    float64   `json:"result"`
+	Dimension string    `json:"dimension"`
+}
+
+type historySession struct {
+	entries  []HistoryEntry
+	lastSeen time.Time
+}
+
+// ConversionHistory keeps the recent conversions of each browser session
+// in memory.
+type ConversionHistory struct {
+	mu       sync.Mutex
+	sessions map[string]*historySession
+}
+
+// NewConversionHistory returns an empty history store.
+func NewConversionHistory() *ConversionHistory {
+	return &ConversionHistory{sessions: make(map[string]*historySession)}
+}
+
+// Record appends an entry to a session's history, dropping the oldest
+// entries beyond maxHistoryEntries.
+func (h *ConversionHistory) Record(session string, entry HistoryEntry) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sessions[session]
+	if !ok {
+		s = &historySession{}
+		h.sessions[session] = s
+	}
+	s.entries = append(s.entries, entry)
+	if len(s.entries) > maxHistoryEntries {
+		s.entries = append(s.entries[:0], s.entries[len(s.entries)-maxHistoryEntries:]...)
+	}
+	s.lastSeen = entry.Time
+}
+
+// Entries returns a session's history within [from, to), oldest first.
+// Zero times leave that side of the range open.
+func (h *ConversionHistory) Entries(session string, from, to time.Time) []HistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	entries := []HistoryEntry{}
+	s, ok := h.sessions[session]
+	if !ok {
+		return entries
+	}
+	for _, entry := range s.entries {
+		if (!from.IsZero() && entry.Time.Before(from)) || (!to.IsZero() && !entry.Time.Before(to)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// Prune drops sessions that have been idle for longer than historyTTL.
+func (h *ConversionHistory) Prune(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for id, s := range h.sessions {
+		if now.Sub(s.lastSeen) > historyTTL {
+			delete(h.sessions, id)
+		}
+	}
+}
+
+// historySessionID returns the caller's session ID from its cookie, issuing
+// a new cookie when create is set and the caller has none.
+func historySessionID(w http.ResponseWriter, r *http.Request, create bool) string {
+	if cookie, err := r.Cookie(historyCookie); err == nil && cookie.Value != "" {
+		return cookie.Value
+	}
+	if !create {
+		return ""
+	}
+	id := randomHex(16)
+	http.SetCookie(w, &http.Cookie{
+		Name:     historyCookie,
+		Value:    id,
+		Path:     "/",
+		MaxAge:   int(historyTTL / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return id
+}
+
+// parseHistoryTime accepts a date (2006-01-02) or an RFC 3339 timestamp.
+// A bare date used as the end of a range includes that whole day.
+func parseHistoryTime(value string, end bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC 3339", value)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// Handler for the history export endpoint
+func historyExportHandler(history *ConversionHistory) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+		}
+
+		query := r.URL.Query()
+		format := query.Get("format")
+		if format == "" {
+			format = "json"
+		}
+		if format != "json" && format != "csv" {
+			fail(http.StatusBadRequest, fmt.Sprintf("unknown format %q (use csv or json)", format))
+			return
+		}
+		from, err := parseHistoryTime(query.Get("from"), false)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		to, err := parseHistoryTime(query.Get("to"), true)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		writeHistory(w, format, history.Entries(historySessionID(w, r, false), from, to))
+	}
+}
+
+// writeHistory sends the entries as a file download.
+func writeHistory(w http.ResponseWriter, format string, entries []HistoryEntry) {
+	filename := "goverter-history-" + time.Now().UTC().Format("20060102") + "." + format
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	out := csv.NewWriter(w)
+	out.Write([]string{"time", "value", "from", "to", "result", "dimension"})
+	for _, entry := range entries {
+		out.Write([]string{
+			entry.Time.UTC().Format(time.RFC3339),
+			strconv.FormatFloat(entry.Value, 'g', -1, 64),
+			entry.From,
+			entry.To,
+			strconv.FormatFloat(entry.Result, 'g', -1, 64),
+			entry.Dimension,
+		})
+	}
+	out.Flush()
+}
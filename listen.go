@@ -0,0 +1,161 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Listen addresses besides TCP host:port pairs.
+const (
+	unixAddrPrefix = "unix:"   // "unix:/run/goverter/goverter.sock"
+	systemdAddr    = "systemd" // sockets passed by systemd socket activation
+)
+
+// systemdFirstFD is the first file descriptor systemd passes sockets on
+// (SD_LISTEN_FDS_START).
+const systemdFirstFD = 3
+
+// listen opens the sockets the server accepts connections on, as selected
+// by addr: a TCP address, a Unix socket path prefixed by "unix:", or
+// "systemd" for the sockets inherited through socket activation.
+func listen(addr string, socketMode os.FileMode) ([]net.Listener, error) {
+	switch {
+	case addr == systemdAddr:
+		return systemdListeners()
+	case strings.HasPrefix(addr, unixAddrPrefix):
+		l, err := listenUnix(strings.TrimPrefix(addr, unixAddrPrefix), socketMode)
+		if err != nil {
+			return nil, err
+		}
+		return []net.Listener{l}, nil
+	}
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return []net.Listener{l}, nil
+}
+
+// listenUnix listens on a Unix socket at path with the given permissions.
+// A socket file left behind by a previous run is removed first, unless a
+// live process still accepts connections on it.
+func listenUnix(path string, mode os.FileMode) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	// The socket is created with the umask applied; set the mode explicitly
+	// so the proxy's group can connect
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("setting socket permissions: %w", err)
+	}
+	return l, nil
+}
+
+// systemdListeners takes over the sockets systemd passed to this process
+// (see sd_listen_fds(3)). The LISTEN_* variables are cleared so child
+// processes don't mistake the sockets for their own.
+func systemdListeners() ([]net.Listener, error) {
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, errors.New("no sockets passed by systemd: LISTEN_PID is not this process")
+	}
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count < 1 {
+		return nil, errors.New("no sockets passed by systemd: LISTEN_FDS is not set")
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	listeners := make([]net.Listener, 0, count)
+	for i := 0; i < count; i++ {
+		fd := systemdFirstFD + i
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		// FileListener duplicates the descriptor, so the original is closed
+		f := os.NewFile(uintptr(fd), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, open := range listeners {
+				open.Close()
+			}
+			return nil, fmt.Errorf("socket %s: %w", name, err)
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// serve runs server on every listener, over TLS when a certificate is
+// configured, and returns the first error any of them stops with.
+func serve(server *http.Server, listeners []net.Listener, certFile, keyFile string) error {
+	errs := make(chan error, len(listeners))
+	for _, l := range listeners {
+		go func(l net.Listener) {
+			if certFile != "" {
+				errs <- server.ServeTLS(l, certFile, keyFile)
+				return
+			}
+			errs <- server.Serve(l)
+		}(l)
+	}
+	return <-errs
+}
+
+// listenerURL describes where l serves the app, for the startup log.
+func listenerURL(l net.Listener, scheme, basePath string) string {
+	addr := l.Addr()
+	if addr.Network() == "unix" {
+		return fmt.Sprintf("%s (%s over Unix socket)", addr.String(), scheme)
+	}
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return scheme + "://" + addr.String() + basePath + "/"
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+	return scheme + "://" + net.JoinHostPort(host, port) + basePath + "/"
+}
+
+// validListenAddr reports why addr can't be listened on, if it can't.
+func validListenAddr(addr string) error {
+	switch {
+	case addr == "":
+		return errors.New("must not be empty")
+	case addr == systemdAddr:
+		return nil
+	case strings.HasPrefix(addr, unixAddrPrefix):
+		if strings.TrimPrefix(addr, unixAddrPrefix) == "" {
+			return errors.New("unix: needs a socket path")
+		}
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return fmt.Errorf("must be host:port, unix:/path or systemd: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// LogFile is the server log written to a file. The log-rotate job calls
+// Rotate, which moves the file aside once it has grown past its size
+// limit: goverter.log becomes goverter.log.1, goverter.log.1 becomes
+// goverter.log.2, and so on, dropping the oldest beyond the backups kept.
+type LogFile struct {
+	path     string
+	maxBytes int64 // Size at which Rotate moves the file aside; zero never rotates
+	backups  int   // Rotated files kept
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenLogFile opens the log file at path for appending, creating it if needed.
+func OpenLogFile(path string, maxBytes int64, backups int) (*LogFile, error) {
+	l := &LogFile{path: path, maxBytes: maxBytes, backups: backups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// open (re)opens the file and reads its current size. The caller holds
+// l.mu, or has not shared l yet.
+func (l *LogFile) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// Write appends p to the log file.
+func (l *LogFile) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n, err := l.file.Write(p)
+	l.size += int64(n)
+	return n, err
+}
+
+// backupPath returns the path of the nth rotated file.
+func (l *LogFile) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Rotate moves the log file aside and starts a new one if it has reached
+// its size limit, and reports whether it did.
+func (l *LogFile) Rotate() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.maxBytes <= 0 || l.size < l.maxBytes {
+		return false, nil
+	}
+
+	if err := l.file.Close(); err != nil {
+		return false, err
+	}
+	var err error
+	if l.backups <= 0 {
+		err = os.Remove(l.path)
+	} else {
+		os.Remove(l.backupPath(l.backups))
+		for n := l.backups - 1; n >= 1; n-- {
+			if err := os.Rename(l.backupPath(n), l.backupPath(n+1)); err != nil && !os.IsNotExist(err) {
+				return false, err
+			}
+		}
+		err = os.Rename(l.path, l.backupPath(1))
+	}
+	// Keep logging even if the old file could not be moved aside
+	if openErr := l.open(); openErr != nil {
+		return false, openErr
+	}
+	return err == nil, err
+}
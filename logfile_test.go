@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogFileRotate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goverter.log")
+	l, err := OpenLogFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each write fills the file past its limit, so each Rotate moves it aside
+	for _, line := range []string{"first line\n", "second line\n", "third line\n"} {
+		if rotated, err := l.Rotate(); rotated || err != nil {
+			t.Fatalf("Rotate before %q = %v, %v, want nothing to do", line, rotated, err)
+		}
+		if _, err := l.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+		if rotated, err := l.Rotate(); !rotated || err != nil {
+			t.Fatalf("Rotate after %q = %v, %v, want a rotation", line, rotated, err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		path:        "",
+		path + ".1": "third line\n",
+		path + ".2": "second line\n",
+	} {
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("more backups kept than configured: %v", err)
+	}
+}
+
+func TestLogFileReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goverter.log")
+	if err := os.WriteFile(path, []byte("from a previous run\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	// The size of an existing file counts towards the limit
+	l, err := OpenLogFile(path, 10, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated, err := l.Rotate(); !rotated || err != nil {
+		t.Errorf("Rotate = %v, %v, want a rotation", rotated, err)
+	}
+
+	// No limit never rotates
+	l, err = OpenLogFile(path, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Write([]byte("a line longer than any limit\n"))
+	if rotated, err := l.Rotate(); rotated || err != nil {
+		t.Errorf("Rotate without a limit = %v, %v", rotated, err)
+	}
+}
@@ -1,304 +1,72 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"html/template"
 	"log"
 	"math"
 	"net/http"
+	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/monsieurr/goverter/converter"
 )
 
-// Unit represents a unit with its conversion factor to the base unit and its dimension.
-type Unit struct {
-	Factor    float64 // Factor to convert to the base unit
-	Dimension string  // e.g., "mass" or "length"
-	Name      string  // Full name of the unit
-	// For temperature conversions, we need offset besides the factor
-	Offset float64 // Used primarily for temperature conversions
-}
+// version is the goverter release, overridable with -ldflags "-X main.version=...".
+var version = "dev"
 
 // ConversionResult represents the result of a conversion operation
 type ConversionResult struct {
-	Success         bool    `json:"success"`
-	Result          float64 `json:"result,omitempty"`
-	FormattedResult string  `json:"formattedResult,omitempty"`
-	Error           string  `json:"error,omitempty"`
-	FromUnit        string  `json:"fromUnit,omitempty"`
-	ToUnit          string  `json:"toUnit,omitempty"`
-	InputValue      float64 `json:"inputValue,omitempty"`
-}
-
-// UnitConverter contains a mapping of unit symbols to their definitions.
-type UnitConverter struct {
-	units map[string]Unit
-}
-
-// NewUnitConverter initializes the converter with all unit dimensions.
-func NewUnitConverter() *UnitConverter {
-	return &UnitConverter{
-		units: map[string]Unit{
-			// Mass units (base = gram)
-			"mg": {Factor: 0.001, Dimension: "mass", Name: "Milligram"},
-			"g":  {Factor: 1, Dimension: "mass", Name: "Gram"},
-			"kg": {Factor: 1000, Dimension: "mass", Name: "Kilogram"},
-			"t":  {Factor: 1000000, Dimension: "mass", Name: "Tonne"},
-			"oz": {Factor: 28.3495, Dimension: "mass", Name: "Ounce"},
-			"lb": {Factor: 453.59237, Dimension: "mass", Name: "Pound"},
-
-			// Length units (base = meter)
-			"nm": {Factor: 0.000000001, Dimension: "length", Name: "Nanometer"},
-			"µm": {Factor: 0.000001, Dimension: "length", Name: "Micrometer"},
-			"mm": {Factor: 0.001, Dimension: "length", Name: "Millimeter"},
-			"cm": {Factor: 0.01, Dimension: "length", Name: "Centimeter"},
-			"m":  {Factor: 1, Dimension: "length", Name: "Meter"},
-			"km": {Factor: 1000, Dimension: "length", Name: "Kilometer"},
-			"in": {Factor: 0.0254, Dimension: "length", Name: "Inch"},
-			"ft": {Factor: 0.3048, Dimension: "length", Name: "Foot"},
-			"yd": {Factor: 0.9144, Dimension: "length", Name: "Yard"},
-			"mi": {Factor: 1609.344, Dimension: "length", Name: "Mile"},
-
-			// Temperature units (base = Kelvin)
-			// For temperature, we need both factor and offset
-			"C":  {Factor: 1, Offset: 273.15, Dimension: "temperature", Name: "Celsius"},
-			"F":  {Factor: 5.0 / 9.0, Offset: 255.372, Dimension: "temperature", Name: "Fahrenheit"},
-			"K":  {Factor: 1, Offset: 0, Dimension: "temperature", Name: "Kelvin"},
-			"Ra": {Factor: 5.0 / 9.0, Offset: 0, Dimension: "temperature", Name: "Rankine"},
-
-			// Time units (base = second)
-			"ns":   {Factor: 1e-9, Dimension: "time", Name: "Nanosecond"},
-			"µs":   {Factor: 1e-6, Dimension: "time", Name: "Microsecond"},
-			"ms":   {Factor: 1e-3, Dimension: "time", Name: "Millisecond"},
-			"s":    {Factor: 1, Dimension: "time", Name: "Second"},
-			"min":  {Factor: 60, Dimension: "time", Name: "Minute"},
-			"h":    {Factor: 3600, Dimension: "time", Name: "Hour"},
-			"day":  {Factor: 86400, Dimension: "time", Name: "Day"},
-			"week": {Factor: 604800, Dimension: "time", Name: "Week"},
-			"year": {Factor: 31536000, Dimension: "time", Name: "Year (365 days)"},
-
-			// Frequency units (base = hertz)
-			"Hz":  {Factor: 1, Dimension: "frequency", Name: "Hertz"},
-			"kHz": {Factor: 1000, Dimension: "frequency", Name: "Kilohertz"},
-			"MHz": {Factor: 1e6, Dimension: "frequency", Name: "Megahertz"},
-			"GHz": {Factor: 1e9, Dimension: "frequency", Name: "Gigahertz"},
-			"THz": {Factor: 1e12, Dimension: "frequency", Name: "Terahertz"},
-
-			// Speed units (base = meters per second)
-			"m/s":  {Factor: 1, Dimension: "speed", Name: "Meters per second"},
-			"km/h": {Factor: 0.277778, Dimension: "speed", Name: "Kilometers per hour"},
-			"ft/s": {Factor: 0.3048, Dimension: "speed", Name: "Feet per second"},
-			"mph":  {Factor: 0.44704, Dimension: "speed", Name: "Miles per hour"},
-			"knot": {Factor: 0.514444, Dimension: "speed", Name: "Knot"},
-			"mach": {Factor: 340.29, Dimension: "speed", Name: "Mach (at sea level)"},
-
-			// Volume units (base = cubic meter)
-			"m³":    {Factor: 1, Dimension: "volume", Name: "Cubic Meter"},
-			"L":     {Factor: 0.001, Dimension: "volume", Name: "Liter"},
-			"gal":   {Factor: 0.003785411784, Dimension: "volume", Name: "Gallon (US)"},
-			"fl_oz": {Factor: 0.0000295735295625, Dimension: "volume", Name: "Fluid Ounce (US)"},
-
-			// Area units (base = square meter)
-			"m²":   {Factor: 1, Dimension: "area", Name: "Square Meter"},
-			"acre": {Factor: 4046.8564224, Dimension: "area", Name: "Acre"},
-			"ha":   {Factor: 10000, Dimension: "area", Name: "Hectare"},
-
-			// Energy units (base = joule)
-			"J":    {Factor: 1, Dimension: "energy", Name: "Joule"},
-			"cal":  {Factor: 4.184, Dimension: "energy", Name: "Calorie"},
-			"kcal": {Factor: 4184, Dimension: "energy", Name: "Kilocalorie"},
-
-			// Power units (base = watt)
-			"W":  {Factor: 1, Dimension: "power", Name: "Watt"},
-			"HP": {Factor: 735.49875, Dimension: "power", Name: "Horsepower"},
-
-			// Force units (base = newton)
-			"N":   {Factor: 1, Dimension: "force", Name: "Newton"},
-			"lbf": {Factor: 4.4482216153, Dimension: "force", Name: "Pound-force"},
-
-			// Pressure units (base = pascal)
-			"Pa":  {Factor: 1, Dimension: "pressure", Name: "Pascal"},
-			"atm": {Factor: 101325, Dimension: "pressure", Name: "Atmosphere"},
-			"bar": {Factor: 100000, Dimension: "pressure", Name: "Bar"},
-
-			// Data Storage units (base = byte)
-			"B":   {Factor: 1, Dimension: "data_storage", Name: "Byte"},
-			"bit": {Factor: 0.125, Dimension: "data_storage", Name: "Bit"},
-			"KB":  {Factor: 1024, Dimension: "data_storage", Name: "Kilobyte"},
-			"MB":  {Factor: 1048576, Dimension: "data_storage", Name: "Megabyte"},
-			"GB":  {Factor: 1073741824, Dimension: "data_storage", Name: "Gigabyte"},
-
-			// Angle units (base = radian)
-			"rad":    {Factor: 1, Dimension: "angle", Name: "Radian"},
-			"deg":    {Factor: math.Pi / 180, Dimension: "angle", Name: "Degree"},
-			"arcmin": {Factor: math.Pi / 10800, Dimension: "angle", Name: "Minute"},
-			"arcsec": {Factor: math.Pi / 648000, Dimension: "angle", Name: "Second"},
-		},
-	}
-}
-
-// Convert performs the conversion from one unit to another.
-func (uc *UnitConverter) Convert(value float64, from, to string) (float64, error) {
-	unitFrom, ok := uc.units[from]
-	if !ok {
-		return 0, fmt.Errorf("invalid source unit: %s", from)
-	}
-	unitTo, ok := uc.units[to]
-	if !ok {
-		return 0, fmt.Errorf("invalid target unit: %s", to)
-	}
-	if unitFrom.Dimension != unitTo.Dimension {
-		return 0, fmt.Errorf("cannot convert between different dimensions: %s (%s) and %s (%s)",
-			from, unitFrom.Dimension, to, unitTo.Dimension)
-	}
-
-	var result float64
-
-	// Special case for temperature, which needs offset handling
-	if unitFrom.Dimension == "temperature" {
-		// First convert to base unit (Kelvin)
-		baseValue := value*unitFrom.Factor + unitFrom.Offset
-		// Then convert from base unit to target unit
-		result = (baseValue - unitTo.Offset) / unitTo.Factor
-	} else {
-		// Standard conversion via the base unit for other dimensions
-		result = value * unitFrom.Factor / unitTo.Factor
-	}
-
-	// Round to a reasonable number of significant digits to avoid floating point issues
-	precision := 12 // High precision to avoid truncation
-	factor := math.Pow(10, float64(precision))
-	return math.Round(result*factor) / factor, nil
-}
-
-// FormatResult formats the conversion result appropriately based on its magnitude
-func (uc *UnitConverter) FormatResult(result float64, unit string) string {
-	// Use scientific notation for very large or very small numbers
-	absResult := math.Abs(result)
-	if absResult < 0.001 || absResult > 1000000 {
-		return fmt.Sprintf("%.6e %s", result, unit)
-	}
-
-	// For "normal" sized numbers, use appropriate decimal places
-	var decimalPlaces int
-	switch {
-	case absResult >= 1000:
-		decimalPlaces = 0
-	case absResult >= 100:
-		decimalPlaces = 1
-	case absResult >= 10:
-		decimalPlaces = 2
-	case absResult >= 1:
-		decimalPlaces = 3
-	default:
-		// For values less than 1, use more decimal places
-		decimalPlaces = 4
-	}
-
-	formatString := fmt.Sprintf("%%.%df %%s", decimalPlaces)
-	return fmt.Sprintf(formatString, result, unit)
-}
-
-// GetUnitsByDimension returns all units of a specific dimension
-func (uc *UnitConverter) GetUnitsByDimension(dimension string) map[string]Unit {
-	result := make(map[string]Unit)
-	for symbol, unit := range uc.units {
-		if unit.Dimension == dimension {
-			result[symbol] = unit
-		}
-	}
-	return result
-}
-
-// GetAllDimensions returns a slice of all available dimensions
-func (uc *UnitConverter) GetAllDimensions() []string {
-	dimensionMap := make(map[string]bool)
-	for _, unit := range uc.units {
-		dimensionMap[unit.Dimension] = true
-	}
-
-	dimensions := make([]string, 0, len(dimensionMap))
-	for dim := range dimensionMap {
-		dimensions = append(dimensions, dim)
-	}
-	return dimensions
-}
-
-// GetDimensionName returns a human-friendly name for a dimension
-func (uc *UnitConverter) GetDimensionName(dimension string) string {
-	switch dimension {
-	case "volume":
-		return "Volume"
-	case "area":
-		return "Area"
-	case "energy":
-		return "Energy"
-	case "power":
-		return "Power"
-	case "force":
-		return "Force"
-	case "pressure":
-		return "Pressure"
-	case "data_storage":
-		return "Data Storage"
-	case "angle":
-		return "Angle"
-	case "mass":
-		return "Mass"
-	case "length":
-		return "Length"
-	case "temperature":
-		return "Temperature"
-	case "time":
-		return "Time"
-	case "frequency":
-		return "Frequency"
-	case "speed":
-		return "Speed"
-	default:
-		return dimension
-	}
+	Success         bool     `json:"success"`
+	Result          *float64 `json:"result,omitempty"`
+	FormattedResult string   `json:"formattedResult,omitempty"`
+	Error           string   `json:"error,omitempty"`
+	FromUnit        string   `json:"fromUnit,omitempty"`
+	ToUnit          string   `json:"toUnit,omitempty"`
+	InputValue      *float64 `json:"inputValue,omitempty"`
+	FormattedInput  string   `json:"formattedInput,omitempty"` // e.g. "5 lb"
+	Dimension       string   `json:"dimension,omitempty"`      // Of the source unit
+	Suggestions     []string `json:"suggestions,omitempty"`    // Known units close to an unknown one
+
+	// Context for displaying a result, e.g. "1 lb = 0.454 kg"
+	Factor           *float64 `json:"factor,omitempty"`           // Target units per source unit; absent for non-linear conversions
+	Forward          string   `json:"forward,omitempty"`          // One source unit in target units
+	Reverse          *float64 `json:"reverse,omitempty"`          // One target unit in source units
+	FormattedReverse string   `json:"formattedReverse,omitempty"` // e.g. "1 kg = 2.205 lb"
+	BaseValue        *float64 `json:"baseValue,omitempty"`        // The input in the base unit of its dimension
+	BaseUnit         string   `json:"baseUnit,omitempty"`
+	Context          string   `json:"context,omitempty"` // What a bridged result depends on, e.g. "gravity=9.80665"
 }
 
 // TemplateData represents the data passed to the HTML template
 type TemplateData struct {
-	Units          map[string]map[string]Unit // Map of dimensions to units
+	Units          map[string]map[string]converter.Unit // Map of dimensions to units
 	Dimensions     []string
 	DimensionNames map[string]string
 	CurrentYear    int
 }
 
 // Handler for the homepage
-func homeHandler(uc *UnitConverter) http.HandlerFunc {
+func homeHandler(uc *converter.UnitConverter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != "/" {
-			http.NotFound(w, r)
-			return
-		}
-
-		// Organize units by dimension
-		unitsByDimension := make(map[string]map[string]Unit)
-		for _, dim := range uc.GetAllDimensions() {
-			unitsByDimension[dim] = uc.GetUnitsByDimension(dim)
-		}
-
-		// Create dimension names map
-		dimensionNames := make(map[string]string)
-		for _, dim := range uc.GetAllDimensions() {
-			dimensionNames[dim] = uc.GetDimensionName(dim)
-		}
-
-		// Create template data
+		// Create template data from the registry's precomputed indexes
+		reg := uc.Snapshot()
 		data := TemplateData{
-			Units:          unitsByDimension,
-			Dimensions:     uc.GetAllDimensions(),
-			DimensionNames: dimensionNames,
+			Units:          reg.UnitsByDimension(),
+			Dimensions:     reg.Dimensions(),
+			DimensionNames: reg.DimensionNames(),
 			CurrentYear:    time.Now().Year(),
 		}
 
 		// Load template from index.html file
-		tmpl, err := template.ParseFiles("templates/index.html")
+		tmpl, err := template.New("index.html").Funcs(pageFuncs(r)).ParseFiles("templates/index.html")
 		if err != nil {
 			http.Error(w, "Error loading template: "+err.Error(), http.StatusInternalServerError)
 			log.Printf("Error loading template: %v", err)
@@ -313,7 +81,7 @@ func homeHandler(uc *UnitConverter) http.HandlerFunc {
 }
 
 // Handler for the unit info endpoint
-func unitInfoHandler(uc *UnitConverter) http.HandlerFunc {
+func unitInfoHandler(uc *converter.UnitConverter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		unitSymbol := r.URL.Query().Get("unit")
 		if unitSymbol == "" {
@@ -321,7 +89,7 @@ func unitInfoHandler(uc *UnitConverter) http.HandlerFunc {
 			return
 		}
 
-		unit, ok := uc.units[unitSymbol]
+		symbol, unit, ok := uc.Snapshot().Lookup(unitSymbol)
 		if !ok {
 			http.Error(w, "Invalid unit symbol", http.StatusBadRequest)
 			return
@@ -329,16 +97,21 @@ func unitInfoHandler(uc *UnitConverter) http.HandlerFunc {
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(map[string]interface{}{
-			"symbol":    unitSymbol,
-			"name":      unit.Name,
-			"dimension": unit.Dimension,
-			"factor":    unit.Factor,
+			"symbol":       symbol,
+			"name":         unit.Name,
+			"dimension":    unit.Dimension,
+			"factor":       unit.Factor,
+			"description":  unit.Description,
+			"source":       unit.Source,
+			"system":       unit.System,
+			"typicalUse":   unit.TypicalUse,
+			"referenceUrl": unit.ReferenceURL,
 		})
 	}
 }
 
 // Handler for getting units by dimension
-func unitsByDimensionHandler(uc *UnitConverter) http.HandlerFunc {
+func unitsByDimensionHandler(uc *converter.UnitConverter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		dimension := r.URL.Query().Get("dimension")
 		if dimension == "" {
@@ -365,99 +138,477 @@ func unitsByDimensionHandler(uc *UnitConverter) http.HandlerFunc {
 				Name:   unit.Name,
 			})
 		}
+		// Stable order so identical catalogs produce identical responses
+		sort.Slice(unitInfos, func(i, j int) bool {
+			return unitInfos[i].Symbol < unitInfos[j].Symbol
+		})
 
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(unitInfos)
 	}
 }
 
+// writeJSON sends v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
 // Handler for the conversion endpoint
-func convertHandler(uc *UnitConverter) http.HandlerFunc {
+func convertHandler(uc *converter.UnitConverter, stats *ConversionStats, history *ConversionHistory, audit *AuditLog) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set appropriate headers
 		w.Header().Set("Content-Type", "application/json")
 
-		if r.Method != http.MethodPost {
+		// Parse form data
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
 			result := ConversionResult{
 				Success: false,
-				Error:   "Method not allowed. Please use POST.",
+				Error:   message,
 			}
-			w.WriteHeader(http.StatusMethodNotAllowed)
+			stats.RecordError()
+			w.WriteHeader(status)
 			json.NewEncoder(w).Encode(result)
 			return
 		}
 
-		// Parse form data
-		if err := r.ParseForm(); err != nil {
+		valueStr := r.FormValue("value")
+		fromUnit := r.FormValue("from")
+		toUnit := r.FormValue("to")
+		notation, err := converter.ParseNotation(r.FormValue("notation"))
+		places := -1 // Per-request precision, -1 when not given
+		if err == nil && r.FormValue("precision") != "" {
+			places, err = converter.ParsePrecision(r.FormValue("precision"))
+		}
+		var gravity, reference float64
+		if err == nil {
+			gravity, err = converter.ParseGravity(r.FormValue("gravity"))
+		}
+		if err == nil {
+			reference, err = uc.ParseReferencePressure(r.FormValue("reference"))
+		}
+		var ingredient string
+		var density float64
+		if err == nil && r.FormValue("ingredient") != "" {
+			ingredient, density, err = converter.LookupIngredient(r.FormValue("ingredient"))
+		}
+		if err != nil {
 			result := ConversionResult{
 				Success: false,
-				Error:   "Error parsing form data",
+				Error:   err.Error(),
 			}
+			stats.RecordError()
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(result)
 			return
 		}
 
-		valueStr := r.FormValue("value")
-		fromUnit := r.FormValue("from")
-		toUnit := r.FormValue("to")
-
 		// Validate input
 		if valueStr == "" || fromUnit == "" || toUnit == "" {
 			result := ConversionResult{
 				Success: false,
 				Error:   "All fields (value, from, to) are required",
 			}
+			stats.RecordError()
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(result)
 			return
 		}
 
-		value, err := strconv.ParseFloat(valueStr, 64)
+		value, err := converter.ParseNumber(valueStr, requestDecimalComma(r))
 		if err != nil {
 			result := ConversionResult{
 				Success: false,
 				Error:   "Invalid value: must be a number",
 			}
+			stats.RecordError()
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(result)
 			return
 		}
 
-		// Perform the conversion
-		result, err := uc.Convert(value, fromUnit, toUnit)
+		// Perform the conversion. Volume and mass are bridged through an
+		// ingredient's density, mass and force through gravity; sound
+		// pressure levels use the reference pressure. Bridged results are
+		// flagged with the context they depend on.
+		convert := func(value float64, from, to string) (float64, bool, error) {
+			switch {
+			case uc.IsSoundPressureLevel(from) || uc.IsSoundPressureLevel(to):
+				result, err := uc.ConvertWithReference(r.Context(), value, from, to, reference)
+				return result, false, err
+			case ingredient != "":
+				return uc.ConvertWithIngredient(r.Context(), value, from, to, density)
+			default:
+				return uc.ConvertWithGravity(r.Context(), value, from, to, gravity)
+			}
+		}
+		ctxNote := "gravity=" + strconv.FormatFloat(gravity, 'g', -1, 64)
+		note := fmt.Sprintf(" (weight at g = %g m/s²)", gravity)
+		if ingredient != "" {
+			ctxNote = "ingredient=" + ingredient
+			note = fmt.Sprintf(" (%s at %g g/mL)", ingredient, density)
+		}
+		result, bridged, err := convert(value, fromUnit, toUnit)
 		if err != nil {
 			errorResult := ConversionResult{
 				Success: false,
 				Error:   err.Error(),
 			}
+			var unknown *converter.UnknownUnitError
+			if errors.As(err, &unknown) {
+				errorResult.Suggestions = unknown.Suggestions
+			}
+			var mismatch *converter.DimensionMismatchError
+			if errors.As(err, &mismatch) && ingredient == "" && converter.IsVolumeMass(mismatch.FromDimension, mismatch.ToDimension) {
+				errorResult.Error += "; add an ingredient (such as flour or sugar) to convert between volume and mass"
+			}
+			stats.RecordError()
 			w.WriteHeader(http.StatusBadRequest)
 			json.NewEncoder(w).Encode(errorResult)
 			return
 		}
 
-		// Return the result as plain text (e.g., "10.00 kg")
-		fmt.Fprintf(w, "%.3f %s", result, toUnit)
+		reg := uc.Snapshot()
+		fromSymbol, unit, _ := reg.Lookup(fromUnit)
+		toSymbol, _, _ := reg.Lookup(toUnit)
+		now := time.Now()
+		stats.RecordSuccess(uc, fromSymbol, toSymbol, unit.Dimension, now)
+		history.Record(historySessionID(w, r, true), HistoryEntry{
+			Time:      now,
+			Value:     value,
+			From:      fromSymbol,
+			To:        toSymbol,
+			Result:    result,
+			Dimension: unit.Dimension,
+		})
+		audit.RecordRequest(r, requestActor(r), AuditConversion, map[string]interface{}{
+			"value":  value,
+			"from":   fromSymbol,
+			"to":     toSymbol,
+			"result": result,
+		})
+
+		// Return the result as plain text (e.g., "10.00 kg"), or in full as
+		// JSON for clients that ask for it. Results that depend on gravity
+		// say so, in the text and in a header.
+		text := formatConvertResult(uc, r, result, fromUnit, toUnit, notation, places)
+		if bridged {
+			w.Header().Set("X-Goverter-Context", ctxNote)
+			text += note
+		}
+		if wantsJSON(r) {
+			details := ConversionResult{
+				Success:         true,
+				Result:          &result,
+				FormattedResult: text,
+				FromUnit:        fromSymbol,
+				ToUnit:          toSymbol,
+				InputValue:      &value,
+				FormattedInput:  converter.WithUnit(strconv.FormatFloat(value, 'f', -1, 64), fromSymbol),
+				Dimension:       unit.Dimension,
+			}
+			if bridged {
+				details.Context = ctxNote
+			}
+			describeConversion(uc, &details, func(value float64, from, to string) (float64, error) {
+				result, _, err := convert(value, from, to)
+				return result, err
+			}, places)
+			if base, ok := reg.BaseUnit(unit.Dimension); ok {
+				baseValue := unit.ToBase(value)
+				details.BaseValue, details.BaseUnit = &baseValue, base
+			}
+			writeJSON(w, http.StatusOK, details)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, text)
+	}
+}
+
+// wantsJSON reports whether a /convert client asked for the full JSON
+// result rather than plain text.
+func wantsJSON(r *http.Request) bool {
+	return r.FormValue("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// describeConversion adds what one unit of each side is worth in the other
+// to a conversion result, and the factor between them when the conversion
+// is linear, so clients can show "1 lb = 0.454 kg" alongside a result.
+func describeConversion(uc *converter.UnitConverter, details *ConversionResult, convert func(value float64, from, to string) (float64, error), places int) {
+	from, to := details.FromUnit, details.ToUnit
+	if one, err := convert(1, from, to); err == nil {
+		details.Forward = converter.WithUnit("1", from) + " = " + formatPlaces(uc, one, from, to, places)
+		// Linear conversions map 0 to 0 and scale evenly
+		zero, errZero := convert(0, from, to)
+		two, errTwo := convert(2, from, to)
+		if errZero == nil && errTwo == nil && zero == 0 && math.Abs(two-2*one) <= 1e-12*math.Abs(two) {
+			details.Factor = &one
+		}
+	}
+	if reverse, err := convert(1, to, from); err == nil {
+		details.Reverse = &reverse
+		details.FormattedReverse = converter.WithUnit("1", to) + " = " + formatPlaces(uc, reverse, to, from, places)
 	}
 }
 
+// formatConvertResult renders a /convert result in the style, notation or
+// precision the request asked for. places is -1 when not requested.
+func formatConvertResult(uc *converter.UnitConverter, r *http.Request, result float64, fromUnit, toUnit string, notation converter.Notation, places int) string {
+	if r.FormValue("style") == "name" {
+		lang := r.FormValue("lang")
+		if lang == "" {
+			lang = r.Header.Get("Accept-Language")
+		}
+		return uc.FormatResultName(result, toUnit, lang)
+	}
+	if places < 0 && r.FormValue("notation") != "" {
+		return uc.FormatResultAs(result, toUnit, notation)
+	}
+	return formatPlaces(uc, result, fromUnit, toUnit, places)
+}
+
+// formatPlaces formats a result converted from fromUnit with the given
+// number of decimals, or with the configured precision (three decimals by
+// default) when places < 0.
+func formatPlaces(uc *converter.UnitConverter, result float64, fromUnit, toUnit string, places int) string {
+	if places < 0 {
+		configured, ok := uc.Precision(toUnit)
+		if !ok {
+			// Decibel levels of quiet sounds give pascals too small for
+			// three decimals, so those fall back to scientific notation
+			if uc.IsDecibelLevel(fromUnit) && result != 0 && math.Abs(result) < 0.0005 {
+				return uc.FormatResult(result, toUnit)
+			}
+			configured = 3
+		}
+		places = configured
+	}
+	return converter.WithUnit(fmt.Sprintf("%.*f", places, result), toUnit)
+}
+
 func main() {
-	uc := NewUnitConverter()
+	command, args := splitCommand(os.Args[1:])
+	cfg, sources, err := loadConfig(args, os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+
+	switch command {
+	case "config print":
+		printConfig(os.Stdout, cfg, sources)
+		return
+	case "units check":
+		if !checkUnits(os.Stdout, cfg) {
+			os.Exit(1)
+		}
+		return
+	case "", "mcp":
+	default:
+		log.Fatalf("Unknown command %q (commands: mcp, config print, units check)", command)
+	}
+
+	var logFile *LogFile
+	if cfg.LogFile != "" {
+		if logFile, err = OpenLogFile(cfg.LogFile, cfg.LogMaxBytes, int(cfg.LogBackups)); err != nil {
+			log.Fatalf("Error opening log file: %v", err)
+		}
+		log.SetOutput(logFile)
+	}
+
+	uc := converter.NewUnitConverter()
+	uc.SetPrecision(cfg.Precision)
+	uc.SetReferencePressure(cfg.ReferencePressure)
+	stats := NewConversionStats()
+	history := NewConversionHistory()
+	quiz := NewQuizStore(uc)
+	shares := NewShareStore()
+	if len(cfg.UnitFiles) > 0 || len(cfg.UnitPacks) > 0 {
+		uc.SetUnitPacks(cfg.UnitPacks)
+		uc.SetUnitFiles(cfg.UnitFiles)
+		if _, err := uc.Reload(); err != nil {
+			log.Fatalf("Error loading unit definitions: %v", err)
+		}
+	}
+
+	// "goverter mcp" serves the converter to MCP clients over stdio instead of HTTP
+	if command == "mcp" {
+		runMCPStdio(uc, os.Stdin, os.Stdout)
+		return
+	}
+
+	audit, err := OpenAuditLog(cfg.AuditFile, cfg.AuditRetention, cfg.AuditConversions)
+	if err != nil {
+		log.Fatalf("Error opening audit log: %v", err)
+	}
 
-	// Define handlers
-	http.HandleFunc("/", homeHandler(uc))
-	http.HandleFunc("/convert", convertHandler(uc))
-	http.HandleFunc("/unit-info", unitInfoHandler(uc))
-	http.HandleFunc("/units-by-dimension", unitsByDimensionHandler(uc))
-	http.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir("static"))))
+	// Background jobs
+	scheduler := NewScheduler()
+	scheduler.Add(Job{
+		Name:     "stats-prune",
+		Interval: 10 * time.Minute,
+		Jitter:   time.Minute,
+		Run: func(ctx context.Context) error {
+			stats.Prune(time.Now())
+			return nil
+		},
+	})
+	scheduler.Add(Job{
+		Name:     "history-prune",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			history.Prune(time.Now())
+			return nil
+		},
+	})
+	scheduler.Add(Job{
+		Name:     "quiz-prune",
+		Interval: 10 * time.Minute,
+		Jitter:   time.Minute,
+		Run: func(ctx context.Context) error {
+			quiz.Prune(time.Now())
+			return nil
+		},
+	})
+	scheduler.Add(Job{
+		Name:     "share-prune",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			shares.Prune(time.Now())
+			return nil
+		},
+	})
+	scheduler.Add(Job{
+		Name:     "audit-prune",
+		Interval: time.Hour,
+		Jitter:   5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return audit.Prune(time.Now())
+		},
+	})
+	scheduler.Add(Job{
+		Name:     "stats-popularity",
+		Interval: time.Minute,
+		Jitter:   10 * time.Second,
+		Run: func(ctx context.Context) error {
+			stats.RecomputePopularity(time.Now())
+			return nil
+		},
+	})
+	if logFile != nil {
+		scheduler.Add(Job{
+			Name:     "log-rotate",
+			Interval: time.Minute,
+			Jitter:   10 * time.Second,
+			Run: func(ctx context.Context) error {
+				rotated, err := logFile.Rotate()
+				if rotated {
+					log.Printf("Log file rotated to %s", logFile.backupPath(1))
+				}
+				return err
+			},
+		})
+	}
+	scheduler.Start(context.Background())
 
-	// Add basic middleware for logging
-	loggedRouter := logMiddleware(http.DefaultServeMux)
+	// Notify webhooks whenever the unit catalog changes
+	webhooks, err := OpenWebhookDispatcher(cfg.WebhooksFile)
+	if err != nil {
+		log.Fatalf("Error opening webhooks file: %v", err)
+	}
+	uc.OnChange(func(diff converter.RegistryDiff) {
+		webhooks.Notify(EventCatalogUpdated, diff)
+	})
+
+	apiKeys, err := cfg.apiKeys()
+	if err != nil {
+		log.Fatalf("Error loading API keys: %v", err)
+	}
+	keys := NewAPIKeyStore(apiKeys, cfg.APIRequireKey)
+	if keys.Enabled() {
+		names := make([]string, len(apiKeys))
+		for i, key := range apiKeys {
+			names[i] = key.Name
+		}
+		audit.Record(AuditEntry{Action: AuditAPIKeysLoad, Actor: "system", Details: map[string]interface{}{"keys": names}})
+	}
+
+	// Define handlers. The router answers OPTIONS, HEAD and refused methods
+	router := NewRouter()
+	router.HandleFunc("/{$}", homeHandler(uc), http.MethodGet)
+	registryRoutes(router, uc, cfg.AdminToken, stats, history, audit, shares)
+	router.HandleFunc("/api/constants", constantsHandler, http.MethodGet)
+	router.HandleFunc("/api/encode", encodeHandler, http.MethodPost)
+	router.HandleFunc("/api/stats", statsHandler(stats), http.MethodGet)
+	router.HandleFunc("/api/usage", usageHandler(keys), http.MethodGet)
+	router.HandleFunc("/api/history/export", historyExportHandler(history), http.MethodGet)
+	router.HandleFunc("/api/quiz/new", quizHandler(quiz), http.MethodPost)
+	router.HandleFunc("/api/quiz/answer", quizHandler(quiz), http.MethodPost)
+	router.HandleFunc("/api/quiz/score", quizHandler(quiz), http.MethodGet)
+	router.Handle("/static/", staticAssets.Handler(), http.MethodGet)
+	router.HandleFunc("/widget.js", widgetScriptHandler, http.MethodGet)
+	router.HandleFunc("/widget", widgetHandler(uc), http.MethodGet)
+	router.HandleFunc("/integrations/slack", slackHandler(uc, cfg.SlackSigningSecret), http.MethodPost)
+	mcpTransport := newMCPSSE(NewMCPServer(uc))
+	router.HandleFunc("/mcp/sse", mcpTransport.streamHandler, http.MethodGet)
+	router.HandleFunc("/mcp/message", mcpTransport.messageHandler, http.MethodPost)
+	router.Handle("/admin/reload", requireAdmin(cfg.AdminToken, reloadHandler(uc, audit)), http.MethodPost)
+	router.Handle("/admin/jobs", requireAdmin(cfg.AdminToken, jobsHandler(scheduler)), http.MethodGet)
+	router.Handle("/admin/audit", requireAdmin(cfg.AdminToken, auditExportHandler(audit)), http.MethodGet)
+	router.Handle("/admin/webhooks", requireAdmin(cfg.AdminToken, webhooksHandler(webhooks, audit)), http.MethodGet, http.MethodPost, http.MethodDelete)
+
+	// Namespaces serve the registry-bound endpoints again over their own registries
+	namespaceKeys, _ := parseNamespaceKeys(cfg.NamespaceKeys)
+	namespaceConfigs, _ := parseNamespaces(cfg.Namespaces)
+	namespaces, err := NewNamespaces(uc, namespaceConfigs, namespaceKeys, func(nsRouter *Router, nsuc *converter.UnitConverter) {
+		registryRoutes(nsRouter, nsuc, cfg.AdminToken, stats, history, audit, shares)
+	})
+	if err != nil {
+		log.Fatalf("Error loading namespaces: %v", err)
+	}
+
+	// Add basic middleware for logging, with the trace ID of each request,
+	// base path stripping, API key metering and namespace routing
+	basePath, _ := NormalizeBasePath(cfg.BasePath)
+	loggedRouter := traceMiddleware(logMiddleware(basePathMiddleware(basePath, keys.Middleware(namespaces.Middleware(router)))))
 
 	// Start server
-	port := ":8080"
-	log.Printf("Server started on http://localhost%s", port)
-	log.Fatal(http.ListenAndServe(port, loggedRouter))
+	server := newServer(cfg, loggedRouter)
+	listeners, err := listen(cfg.Addr, cfg.SocketMode)
+	if err != nil {
+		log.Fatalf("Error listening on %s: %v", cfg.Addr, err)
+	}
+	scheme := "http"
+	if cfg.TLSCert != "" {
+		scheme = "https"
+	}
+	for _, l := range listeners {
+		log.Printf("Server started on %s", listenerURL(l, scheme, basePath))
+	}
+	log.Fatal(serve(server, listeners, cfg.TLSCert, cfg.TLSKey))
+}
+
+// splitCommand separates a subcommand ("mcp", "config print", "units check") from the flags
+// that follow it. Plain flags run the web server.
+func splitCommand(args []string) (string, []string) {
+	switch {
+	case len(args) > 0 && args[0] == "mcp":
+		return "mcp", args[1:]
+	case len(args) > 1 && args[0] == "config" && args[1] == "print":
+		return "config print", args[2:]
+	case len(args) > 1 && args[0] == "units" && args[1] == "check":
+		return "units check", args[2:]
+	case len(args) > 0 && !strings.HasPrefix(args[0], "-"):
+		return strings.Join(args, " "), nil
+	}
+	return "", args
 }
 
 // Basic logging middleware
@@ -469,6 +620,19 @@ func logMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 
 		// Log after request is processed
+		if traceID := TraceID(r.Context()); traceID != "" {
+			log.Printf("%s %s %s trace=%s", r.Method, r.RequestURI, time.Since(start), traceID)
+			return
+		}
 		log.Printf("%s %s %s", r.Method, r.RequestURI, time.Since(start))
 	})
 }
+
+// requestDecimalComma reports whether numbers in r should be read with a
+// decimal comma, from its lang parameter or Accept-Language header.
+func requestDecimalComma(r *http.Request) bool {
+	if lang := r.FormValue("lang"); lang != "" {
+		return converter.UsesDecimalComma(lang)
+	}
+	return converter.UsesDecimalComma(r.Header.Get("Accept-Language"))
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+func TestFormatPlaces(t *testing.T) {
+	uc := converter.NewUnitConverter()
+	tests := []struct {
+		result   float64
+		from, to string
+		places   int
+		want     string
+	}{
+		{0.000001, "mm", "km", -1, "0.000 km"},
+		{1234.5678, "m", "km", -1, "1234.568 km"},
+		{1234.5678, "m", "km", 1, "1234.6 km"},
+		// Quiet sound levels are too small for three decimals
+		{2e-5, "dB SPL", "Pa", -1, "2.000000e-05 Pa"},
+		{1e-12, "dB SIL", "W/m²", -1, "1.000000e-12 W/m²"},
+		{2e-5, "dB SPL", "Pa", 2, "0.00 Pa"},
+		{2e-5, "µPa", "Pa", -1, "0.000 Pa"},
+	}
+	for _, tt := range tests {
+		if got := formatPlaces(uc, tt.result, tt.from, tt.to, tt.places); got != tt.want {
+			t.Errorf("formatPlaces(%g, %s, %s, %d) = %q, want %q", tt.result, tt.from, tt.to, tt.places, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// Limits on the size of a conversion matrix.
+const (
+	maxMatrixValues  = 1000
+	maxMatrixTargets = 50
+)
+
+// MatrixResult is the response of the matrix endpoint.
+type MatrixResult struct {
+	Success bool                  `json:"success"`
+	Error   string                `json:"error,omitempty"`
+	From    string                `json:"from,omitempty"`
+	To      []string              `json:"to,omitempty"`
+	Rows    []converter.MatrixRow `json:"rows,omitempty"`
+}
+
+// splitList reads a list parameter given either repeated or comma-separated.
+func splitList(params []string) []string {
+	var items []string
+	for _, param := range params {
+		for _, item := range strings.Split(param, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+// splitValues reads a list of numbers given either repeated or separated by
+// commas. A parameter containing semicolons is split on those instead, so
+// values can carry decimal commas or thousands separators ("1,5;2,25").
+func splitValues(params []string) []string {
+	var values []string
+	for _, param := range params {
+		if !strings.Contains(param, ";") {
+			values = append(values, splitList([]string{param})...)
+			continue
+		}
+		for _, value := range strings.Split(param, ";") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// Handler for the matrix conversion endpoint
+func matrixHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, MatrixResult{Success: false, Error: message})
+		}
+
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			fail(status, message)
+			return
+		}
+
+		from := r.Form.Get("from")
+		to := splitList(r.Form["to"])
+		rawValues := splitValues(r.Form["values"])
+		if from == "" || len(to) == 0 || len(rawValues) == 0 {
+			fail(http.StatusBadRequest, "All fields (values, from, to) are required")
+			return
+		}
+		if len(rawValues) > maxMatrixValues || len(to) > maxMatrixTargets {
+			fail(http.StatusBadRequest, "Too many values or target units (limits: "+
+				strconv.Itoa(maxMatrixValues)+" values, "+strconv.Itoa(maxMatrixTargets)+" units)")
+			return
+		}
+
+		values := make([]float64, len(rawValues))
+		for i, raw := range rawValues {
+			value, err := converter.ParseNumber(raw, requestDecimalComma(r))
+			if err != nil {
+				fail(http.StatusBadRequest, "Invalid value: "+raw+" is not a number")
+				return
+			}
+			values[i] = value
+		}
+
+		rows, err := uc.ConvertMatrix(r.Context(), values, from, to)
+		if err != nil {
+			result := MatrixResult{Success: false, Error: err.Error()}
+			status := http.StatusBadRequest
+			if !errors.Is(err, converter.ErrUnknownUnit) && !errors.Is(err, converter.ErrDimensionMismatch) && !errors.Is(err, converter.ErrOutOfRange) {
+				status = http.StatusInternalServerError
+			}
+			writeJSON(w, status, result)
+			return
+		}
+
+		switch r.Form.Get("format") {
+		case "csv":
+			writeMatrixCSV(w, from, to, rows)
+			return
+		case "xlsx":
+			writeMatrixXLSX(w, uc, from, to, rows)
+			return
+		}
+		writeJSON(w, http.StatusOK, MatrixResult{Success: true, From: from, To: to, Rows: rows})
+	}
+}
+
+// writeMatrixCSV writes the matrix as a CSV download with one row per value.
+func writeMatrixCSV(w http.ResponseWriter, from string, to []string, rows []converter.MatrixRow) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="conversion-matrix.csv"`)
+
+	out := csv.NewWriter(w)
+	out.Write(append([]string{from}, to...))
+	record := make([]string, len(to)+1)
+	for _, row := range rows {
+		record[0] = strconv.FormatFloat(row.Value, 'g', -1, 64)
+		for j, result := range row.Results {
+			record[j+1] = strconv.FormatFloat(result, 'g', -1, 64)
+		}
+		out.Write(record)
+	}
+	out.Flush()
+}
+
+// writeMatrixXLSX writes the matrix as a spreadsheet download, one column
+// per unit with each formatted to that unit's precision.
+func writeMatrixXLSX(w http.ResponseWriter, uc *converter.UnitConverter, from string, to []string, rows []converter.MatrixRow) {
+	columns := []xlsxColumn{xlsxUnitColumn(uc, from)}
+	for _, symbol := range to {
+		columns = append(columns, xlsxUnitColumn(uc, symbol))
+	}
+	cells := make([][]float64, len(rows))
+	for i, row := range rows {
+		cells[i] = append([]float64{row.Value}, row.Results...)
+	}
+	serveXLSX(w, "conversion-matrix.xlsx", "Conversions", columns, cells)
+}
@@ -0,0 +1,390 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// mcpProtocolVersion is the Model Context Protocol revision implemented here.
+const mcpProtocolVersion = "2024-11-05"
+
+// JSON-RPC 2.0 error codes used by the MCP server.
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// mcpTool describes a tool in the tools/list response.
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// mcpContent is a block of tool output.
+type mcpContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// mcpToolResult is the result of a tools/call request.
+type mcpToolResult struct {
+	Content           []mcpContent `json:"content"`
+	StructuredContent interface{}  `json:"structuredContent,omitempty"`
+	IsError           bool         `json:"isError"`
+}
+
+var mcpTools = []mcpTool{
+	{
+		Name:        "convert",
+		Description: "Convert a value from one unit to another, e.g. 5 kg to lb. Units are given by symbol or alias.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"value": map[string]interface{}{"type": "number", "description": "Value to convert"},
+				"from":  map[string]interface{}{"type": "string", "description": "Source unit symbol, e.g. kg"},
+				"to":    map[string]interface{}{"type": "string", "description": "Target unit symbol, e.g. lb"},
+				"notation": map[string]interface{}{
+					"type": "string", "enum": []string{"auto", "engineering", "si"},
+					"description": "How to format the result: auto (default), engineering or si (automatic SI prefix)",
+				},
+				"style": map[string]interface{}{
+					"type": "string", "enum": []string{"symbol", "name"},
+					"description": "name spells out the unit with correct pluralization, e.g. 3 feet",
+				},
+				"gravity": map[string]interface{}{
+					"type":        "string",
+					"description": "Gravity in m/s² or earth, moon, mars, jupiter, used to convert between mass and force (default 9.80665)",
+				},
+			},
+			"required": []string{"value", "from", "to"},
+		},
+	},
+	{
+		Name:        "search_units",
+		Description: "Search the unit catalog by symbol, name or alias, optionally within one dimension such as length or mass.",
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"query":     map[string]interface{}{"type": "string", "description": "Text to search for"},
+				"dimension": map[string]interface{}{"type": "string", "description": "Optional dimension to restrict the search to"},
+			},
+			"required": []string{"query"},
+		},
+	},
+	{
+		Name:        "parse_expression",
+		Description: `Parse and evaluate a conversion written in plain text, such as "100 F to C" or "3.5 mi in km".`,
+		InputSchema: map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"expression": map[string]interface{}{"type": "string", "description": "Conversion expression"},
+			},
+			"required": []string{"expression"},
+		},
+	},
+}
+
+// MCPServer exposes the converter as Model Context Protocol tools.
+type MCPServer struct {
+	uc *converter.UnitConverter
+}
+
+// NewMCPServer returns an MCP server backed by uc.
+func NewMCPServer(uc *converter.UnitConverter) *MCPServer {
+	return &MCPServer{uc: uc}
+}
+
+// HandleMessage processes one JSON-RPC message and returns the encoded
+// response, or nil for notifications. Tool calls run with ctx.
+func (s *MCPServer) HandleMessage(ctx context.Context, data []byte) []byte {
+	var req rpcRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return encodeRPC(rpcResponse{ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, "Parse error"}})
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return encodeRPC(rpcResponse{ID: orNull(req.ID), Error: &rpcError{rpcInvalidRequest, "Invalid request"}})
+	}
+
+	result, rpcErr := s.dispatch(ctx, req)
+	if req.ID == nil {
+		// Notifications never get a response
+		return nil
+	}
+	return encodeRPC(rpcResponse{ID: req.ID, Result: result, Error: rpcErr})
+}
+
+func (s *MCPServer) dispatch(ctx context.Context, req rpcRequest) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		return map[string]interface{}{
+			"protocolVersion": mcpProtocolVersion,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+			"serverInfo":      map[string]interface{}{"name": "goverter", "version": version},
+		}, nil
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": mcpTools}, nil
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{rpcInvalidParams, "Invalid params"}
+		}
+		return s.callTool(ctx, params.Name, params.Arguments)
+	default:
+		return nil, &rpcError{rpcMethodNotFound, "Method not found: " + req.Method}
+	}
+}
+
+// callTool runs a tool. Conversion failures are reported as tool errors so
+// the model can read them; only malformed calls are protocol errors.
+func (s *MCPServer) callTool(ctx context.Context, name string, args json.RawMessage) (interface{}, *rpcError) {
+	switch name {
+	case "convert":
+		var in struct {
+			Value    *float64 `json:"value"`
+			From     string   `json:"from"`
+			To       string   `json:"to"`
+			Notation string   `json:"notation"`
+			Style    string   `json:"style"`
+			Gravity  string   `json:"gravity"`
+		}
+		if err := json.Unmarshal(args, &in); err != nil || in.Value == nil || in.From == "" || in.To == "" {
+			return nil, &rpcError{rpcInvalidParams, "convert requires value, from and to"}
+		}
+		notation, err := converter.ParseNotation(in.Notation)
+		if err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		gravity, err := converter.ParseGravity(in.Gravity)
+		if err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		result, bridged, err := s.uc.ConvertWithGravity(ctx, *in.Value, in.From, in.To, gravity)
+		if err != nil {
+			return toolError(err), nil
+		}
+		expr := converter.Expression{Value: *in.Value, From: in.From, To: in.To}
+		toolResult := s.conversionResult(expr, result, notation)
+		if in.Style == "name" {
+			toolResult.Content[0].Text = s.uc.FormatResultName(expr.Value, expr.From, converter.DefaultLocale) +
+				" = " + s.uc.FormatResultName(result, expr.To, converter.DefaultLocale)
+		}
+		if bridged {
+			toolResult.Content[0].Text += fmt.Sprintf(" (weight at g = %g m/s²)", gravity)
+			toolResult.StructuredContent.(map[string]interface{})["gravity"] = gravity
+		}
+		return toolResult, nil
+
+	case "search_units":
+		var in struct {
+			Query     string `json:"query"`
+			Dimension string `json:"dimension"`
+		}
+		if err := json.Unmarshal(args, &in); err != nil {
+			return nil, &rpcError{rpcInvalidParams, "search_units requires a query"}
+		}
+		matches := s.uc.SearchUnits(in.Query, in.Dimension)
+		text, _ := json.Marshal(matches)
+		return mcpToolResult{
+			Content:           []mcpContent{{Type: "text", Text: string(text)}},
+			StructuredContent: map[string]interface{}{"units": matches},
+		}, nil
+
+	case "parse_expression":
+		var in struct {
+			Expression string `json:"expression"`
+		}
+		if err := json.Unmarshal(args, &in); err != nil || in.Expression == "" {
+			return nil, &rpcError{rpcInvalidParams, "parse_expression requires an expression"}
+		}
+		expr, result, err := s.uc.EvaluateContext(ctx, in.Expression)
+		if err != nil {
+			return toolError(err), nil
+		}
+		return s.conversionResult(expr, result, converter.NotationAuto), nil
+
+	default:
+		return nil, &rpcError{rpcInvalidParams, "Unknown tool: " + name}
+	}
+}
+
+func (s *MCPServer) conversionResult(expr converter.Expression, result float64, notation converter.Notation) mcpToolResult {
+	text := converter.WithUnit(fmt.Sprintf("%g", expr.Value), expr.From) + " = " + s.uc.FormatResultAs(result, expr.To, notation)
+	return mcpToolResult{
+		Content: []mcpContent{{Type: "text", Text: text}},
+		StructuredContent: map[string]interface{}{
+			"value":  expr.Value,
+			"from":   expr.From,
+			"to":     expr.To,
+			"result": result,
+		},
+	}
+}
+
+func toolError(err error) mcpToolResult {
+	return mcpToolResult{Content: []mcpContent{{Type: "text", Text: err.Error()}}, IsError: true}
+}
+
+func encodeRPC(resp rpcResponse) []byte {
+	resp.JSONRPC = "2.0"
+	data, _ := json.Marshal(resp)
+	return data
+}
+
+func orNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
+
+// ServeStdio answers newline-delimited JSON-RPC messages from r on w until
+// r is exhausted.
+func (s *MCPServer) ServeStdio(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.HandleMessage(context.Background(), line); resp != nil {
+			if _, err := fmt.Fprintf(w, "%s\n", resp); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// mcpSSE implements the MCP HTTP+SSE transport: clients open an event
+// stream at /mcp/sse and post messages to the endpoint it announces.
+type mcpSSE struct {
+	server   *MCPServer
+	mu       sync.Mutex
+	sessions map[string]chan []byte
+}
+
+func newMCPSSE(server *MCPServer) *mcpSSE {
+	return &mcpSSE{server: server, sessions: make(map[string]chan []byte)}
+}
+
+// Handler for the MCP event stream
+func (t *mcpSSE) streamHandler(w http.ResponseWriter, r *http.Request) {
+	// HEAD gets the stream's headers but no session, and must not block
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	// The stream outlives any server write timeout
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	id := randomHex(16)
+	messages := make(chan []byte, 16)
+	t.mu.Lock()
+	t.sessions[id] = messages
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.sessions, id)
+		t.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprintf(w, "event: endpoint\ndata: %s/mcp/message?sessionId=%s\n\n", BasePath(r.Context()), id)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-messages:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// Handler for messages posted to an MCP session
+func (t *mcpSSE) messageHandler(w http.ResponseWriter, r *http.Request) {
+	t.mu.Lock()
+	messages, ok := t.sessions[r.URL.Query().Get("sessionId")]
+	t.mu.Unlock()
+	if !ok {
+		http.Error(w, "Unknown session", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		status, message := bodyError(err, "Error reading request body")
+		http.Error(w, message, status)
+		return
+	}
+	if resp := t.server.HandleMessage(r.Context(), body); resp != nil {
+		select {
+		case messages <- resp:
+		case <-r.Context().Done():
+			return
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// runMCPStdio serves MCP over standard input and output. Logs go to
+// standard error so they don't corrupt the protocol stream.
+func runMCPStdio(uc *converter.UnitConverter, in io.Reader, out io.Writer) {
+	log.Printf("Serving MCP over stdio")
+	if err := NewMCPServer(uc).ServeStdio(in, out); err != nil {
+		log.Fatalf("MCP stdio error: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// namespacePrefix starts the paths of namespaced endpoints: /t/{namespace}/...
+const namespacePrefix = "/t/"
+
+// NamespaceConfig is a namespace and its unit definition files.
+type NamespaceConfig struct {
+	Name  string
+	Files []string
+}
+
+// parseNamespaces reads "name" or "name=file" items. A name may be given
+// several times to layer several files.
+func parseNamespaces(items []string) ([]NamespaceConfig, error) {
+	var namespaces []NamespaceConfig
+	index := make(map[string]int)
+	for _, item := range items {
+		name, file, hasFile := strings.Cut(item, "=")
+		name, file = strings.TrimSpace(name), strings.TrimSpace(file)
+		if err := converter.ValidateNamespaceName(name); err != nil {
+			return nil, err
+		}
+		if hasFile && file == "" {
+			return nil, fmt.Errorf("namespace %s: missing unit file after =", name)
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(namespaces)
+			index[name] = i
+			namespaces = append(namespaces, NamespaceConfig{Name: name})
+		}
+		if hasFile {
+			namespaces[i].Files = append(namespaces[i].Files, file)
+		}
+	}
+	return namespaces, nil
+}
+
+// parseNamespaceKeys reads "key=namespace" items into a map from API key
+// name to namespace.
+func parseNamespaceKeys(items []string) (map[string]string, error) {
+	keys := make(map[string]string, len(items))
+	for _, item := range items {
+		key, namespace, ok := strings.Cut(item, "=")
+		key, namespace = strings.TrimSpace(key), strings.TrimSpace(namespace)
+		if !ok || key == "" || namespace == "" {
+			return nil, fmt.Errorf("invalid item %q, expected key=namespace", item)
+		}
+		if _, dup := keys[key]; dup {
+			return nil, fmt.Errorf("API key %s is assigned to more than one namespace", key)
+		}
+		keys[key] = namespace
+	}
+	return keys, nil
+}
+
+// namespace is a namespace's converter and the endpoints bound to it.
+type namespace struct {
+	uc     *converter.UnitConverter
+	router *Router
+}
+
+// Namespaces routes requests to per-namespace registries, selected by a
+// /t/{namespace}/ path prefix or by the namespace of the request's API key.
+type Namespaces struct {
+	byName map[string]*namespace
+	byKey  map[string]*namespace // API key name -> its namespace
+}
+
+// NewNamespaces creates a converter and endpoints for every namespace.
+// routes registers the registry-bound endpoints for a converter.
+func NewNamespaces(uc *converter.UnitConverter, configs []NamespaceConfig, keys map[string]string, routes func(*Router, *converter.UnitConverter)) (*Namespaces, error) {
+	n := &Namespaces{byName: make(map[string]*namespace), byKey: make(map[string]*namespace)}
+	for _, config := range configs {
+		nsConverter, err := uc.NewNamespace(config.Name, config.Files)
+		if err != nil {
+			return nil, err
+		}
+		router := NewRouter()
+		routes(router, nsConverter)
+		n.byName[config.Name] = &namespace{uc: nsConverter, router: router}
+	}
+	for key, name := range keys {
+		ns, ok := n.byName[name]
+		if !ok {
+			return nil, fmt.Errorf("API key %s: unknown namespace %s", key, name)
+		}
+		n.byKey[key] = ns
+	}
+	return n, nil
+}
+
+// splitNamespacePath splits /t/{namespace}/rest into the namespace and /rest.
+func splitNamespacePath(path string) (name, rest string, ok bool) {
+	after, ok := strings.CutPrefix(path, namespacePrefix)
+	if !ok {
+		return "", "", false
+	}
+	name, rest, ok = strings.Cut(after, "/")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	return name, "/" + rest, true
+}
+
+// Middleware serves namespaced requests from their namespace's endpoints.
+// An API key assigned to a namespace uses it without the path prefix, and
+// can't reach other namespaces. It must run after the API key middleware.
+func (n *Namespaces) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keyNamespace := n.byKey[APIKeyName(r.Context())]
+
+		if name, _, ok := splitNamespacePath(r.URL.Path); ok {
+			ns, known := n.byName[name]
+			if !known {
+				writeJSON(w, http.StatusNotFound, map[string]interface{}{
+					"success": false,
+					"error":   "Unknown namespace " + name,
+				})
+				return
+			}
+			if keyNamespace != nil && keyNamespace != ns {
+				writeJSON(w, http.StatusForbidden, map[string]interface{}{
+					"success": false,
+					"error":   "This API key can't access namespace " + name,
+				})
+				return
+			}
+			http.StripPrefix(namespacePrefix+name, ns.router).ServeHTTP(w, r)
+			return
+		}
+
+		if keyNamespace != nil {
+			if keyNamespace.router.Handles(r) {
+				keyNamespace.router.ServeHTTP(w, r)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// registryRoutes registers the endpoints that resolve units against uc.
+// They are served at the top level for the global registry and under
+// /t/{namespace}/ for each namespace.
+func registryRoutes(router *Router, uc *converter.UnitConverter, adminToken string, stats *ConversionStats, history *ConversionHistory, audit *AuditLog, shares *ShareStore) {
+	router.HandleFunc("/convert", convertHandler(uc, stats, history, audit), http.MethodPost)
+	router.Handle("/unit-info", withCatalogETag(uc, unitInfoHandler(uc)), http.MethodGet)
+	router.Handle("/units-by-dimension", withCatalogETag(uc, unitsByDimensionHandler(uc)), http.MethodGet)
+	router.Handle("/api/units", withCatalogETag(uc, unitsHandler(uc)), http.MethodGet)
+	router.HandleFunc("/api/matrix", matrixHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/range", rangeHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/calc/energy-cost", energyCostHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/calc/energy", energyHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/calc/transfer-time", transferTimeHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/calc/bmi", bmiHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/calc/bmr", bmrHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/calc/body-fat", bodyFatHandler(uc), http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/chart.pdf", chartHandler(uc, "pdf"), http.MethodGet)
+	router.HandleFunc("/api/chart.png", chartHandler(uc, "png"), http.MethodGet)
+	router.HandleFunc("/api/share", shareHandler(uc, shares), http.MethodPost)
+	router.HandleFunc("/s/", sharePageHandler(uc, shares), http.MethodGet)
+	router.Handle("/api/catalog/export", withCatalogETag(uc, catalogExportHandler(uc)), http.MethodGet)
+	router.Handle("/api/catalog/import", requireAdmin(adminToken, catalogImportHandler(uc, audit)), http.MethodPost)
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// namespaceTestHandler serves the registry routes for the global registry
+// and two namespaces, acme and beta, which each define their own pallet.
+// The API key "acme-secret" is assigned to acme, "free-secret" to none.
+func namespaceTestHandler(t *testing.T) http.Handler {
+	t.Helper()
+	dir := t.TempDir()
+	unitFile := func(name string, factor string) string {
+		path := filepath.Join(dir, name+".json")
+		content := `{"units": {"pallet": {"factor": ` + factor + `, "dimension": "mass", "name": "Pallet"}}}`
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	audit, err := OpenAuditLog("", 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, history, shares := NewConversionStats(), NewConversionHistory(), NewShareStore()
+	routes := func(router *Router, uc *converter.UnitConverter) {
+		registryRoutes(router, uc, "", stats, history, audit, shares)
+	}
+
+	uc := converter.NewUnitConverter()
+	configs := []NamespaceConfig{
+		{Name: "acme", Files: []string{unitFile("acme", "500000")}},
+		{Name: "beta", Files: []string{unitFile("beta", "250000")}},
+	}
+	namespaces, err := NewNamespaces(uc, configs, map[string]string{"acme": "acme"}, routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := NewRouter()
+	routes(router, uc)
+	keys := NewAPIKeyStore([]APIKey{
+		{Name: "acme", Secret: "acme-secret"},
+		{Name: "free", Secret: "free-secret"},
+	}, false)
+	return keys.Middleware(namespaces.Middleware(router))
+}
+
+func TestNamespaceSelection(t *testing.T) {
+	handler := namespaceTestHandler(t)
+	tests := []struct {
+		name   string
+		path   string
+		key    string
+		status int
+		factor float64 // Of the pallet, when found
+	}{
+		{"global has no pallet", "/unit-info?unit=pallet", "", http.StatusBadRequest, 0},
+		{"by path", "/t/acme/unit-info?unit=pallet", "", http.StatusOK, 500000},
+		{"by other path", "/t/beta/unit-info?unit=pallet", "", http.StatusOK, 250000},
+		{"by key", "/unit-info?unit=pallet", "acme-secret", http.StatusOK, 500000},
+		{"by key and own path", "/t/acme/unit-info?unit=pallet", "acme-secret", http.StatusOK, 500000},
+		{"key without namespace", "/unit-info?unit=pallet", "free-secret", http.StatusBadRequest, 0},
+		{"key without namespace on a path", "/t/beta/unit-info?unit=pallet", "free-secret", http.StatusOK, 250000},
+		{"unknown key on an unmetered path", "/unit-info?unit=pallet", "wrong", http.StatusBadRequest, 0},
+		{"key for another namespace", "/t/beta/unit-info?unit=pallet", "acme-secret", http.StatusForbidden, 0},
+		{"unknown namespace", "/t/gamma/unit-info?unit=pallet", "", http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(apiKeyHeader, tt.key)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+			if tt.factor == 0 {
+				return
+			}
+			var info struct {
+				Factor float64 `json:"factor"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+				t.Fatal(err)
+			}
+			if info.Factor != tt.factor {
+				t.Errorf("pallet factor %v, want %v", info.Factor, tt.factor)
+			}
+		})
+	}
+}
+
+func TestNamespaceSelectionByKeyOnEveryRoute(t *testing.T) {
+	handler := namespaceTestHandler(t)
+	for _, path := range []string{
+		"/units-by-dimension?dimension=mass",
+		"/api/units",
+		"/api/catalog/export",
+	} {
+		for _, key := range []string{"", "acme-secret"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if key != "" {
+				req.Header.Set(apiKeyHeader, key)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET %s with key %q: status %d", path, key, rec.Code)
+			}
+			if got, want := strings.Contains(rec.Body.String(), `"pallet"`), key != ""; got != want {
+				t.Errorf("GET %s with key %q lists the pallet: %v, want %v", path, key, got, want)
+			}
+		}
+	}
+}
+
+func TestNamespaceCatalogCaching(t *testing.T) {
+	handler := namespaceTestHandler(t)
+	tests := []struct {
+		key, cacheControl string
+	}{
+		{"", catalogCacheControl},
+		{"acme-secret", keyedCatalogCacheControl},
+		{"free-secret", keyedCatalogCacheControl},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/units", nil)
+		if tt.key != "" {
+			req.Header.Set(apiKeyHeader, tt.key)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+			t.Errorf("key %q: Cache-Control %q, want %q", tt.key, got, tt.cacheControl)
+		}
+		if got := rec.Header().Get("Vary"); got != apiKeyHeader {
+			t.Errorf("key %q: Vary %q, want %q", tt.key, got, apiKeyHeader)
+		}
+	}
+}
+
+func TestNamespaceShare(t *testing.T) {
+	handler := namespaceTestHandler(t)
+	share := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("value=2&from=pallet&to=t"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := share("/api/share"); rec.Code != http.StatusBadRequest {
+		t.Errorf("sharing a pallet globally: status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	rec := share("/t/acme/api/share")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("sharing a pallet in acme: status %d: %s", rec.Code, rec.Body.String())
+	}
+	var shared struct {
+		ID     string  `json:"id"`
+		URL    string  `json:"url"`
+		Result float64 `json:"result"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &shared); err != nil {
+		t.Fatal(err)
+	}
+	if shared.Result != 1 || !strings.HasSuffix(shared.URL, "/t/acme/s/"+shared.ID) {
+		t.Errorf("shared %+v, want 1 t behind /t/acme/s/%s", shared, shared.ID)
+	}
+
+	// The link opens in its own namespace only
+	for path, status := range map[string]int{
+		"/t/acme/s/" + shared.ID: http.StatusOK,
+		"/t/beta/s/" + shared.ID: http.StatusNotFound,
+		"/s/" + shared.ID:        http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != status {
+			t.Errorf("GET %s: status %d, want %d", path, rec.Code, status)
+		}
+		if status == http.StatusOK && !strings.Contains(rec.Body.String(), "Pallet") {
+			t.Errorf("GET %s doesn't name the pallet", path)
+		}
+	}
+}
+
+func TestSplitNamespacePath(t *testing.T) {
+	tests := []struct {
+		path, name, rest string
+		ok               bool
+	}{
+		{"/t/acme/convert", "acme", "/convert", true},
+		{"/t/acme/", "acme", "/", true},
+		{"/t/acme", "", "", false},
+		{"/t//convert", "", "", false},
+		{"/convert", "", "", false},
+	}
+	for _, tt := range tests {
+		name, rest, ok := splitNamespacePath(tt.path)
+		if name != tt.name || rest != tt.rest || ok != tt.ok {
+			t.Errorf("splitNamespacePath(%q) = %q, %q, %v, want %q, %q, %v", tt.path, name, rest, ok, tt.name, tt.rest, tt.ok)
+		}
+	}
+}
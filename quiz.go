@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// quizTTL is how long a question can be answered.
+const quizTTL = time.Hour
+
+// quizLevel describes one difficulty: how values are drawn and how close
+// an answer must be.
+type quizLevel struct {
+	tolerance  float64 // Relative error accepted
+	minValue   float64
+	maxValue   float64
+	decimals   int  // Decimals of the generated value
+	commonOnly bool // Restrict to SI and core units
+}
+
+var quizLevels = map[string]quizLevel{
+	"easy":   {tolerance: 0.02, minValue: 1, maxValue: 10, decimals: 0, commonOnly: true},
+	"medium": {tolerance: 0.01, minValue: 1, maxValue: 100, decimals: 1},
+	"hard":   {tolerance: 0.005, minValue: 0.1, maxValue: 1000, decimals: 2},
+}
+
+// QuizQuestion is an exercise as shown to the student. The answer stays on
+// the server.
+type QuizQuestion struct {
+	ID         string  `json:"id"`
+	Question   string  `json:"question"`
+	Value      float64 `json:"value"`
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	Dimension  string  `json:"dimension"`
+	Difficulty string  `json:"difficulty"`
+	Tolerance  float64 `json:"tolerance"` // Relative error accepted, e.g. 0.01 for 1%
+}
+
+// QuizScore tracks a session's progress.
+type QuizScore struct {
+	Answered   int `json:"answered"`
+	Correct    int `json:"correct"`
+	Streak     int `json:"streak"`
+	BestStreak int `json:"bestStreak"`
+
+	lastSeen time.Time
+}
+
+// QuizResult is the grade of a submitted answer.
+type QuizResult struct {
+	Correct  bool      `json:"correct"`
+	Answer   float64   `json:"answer"`
+	Expected float64   `json:"expected"`
+	Score    QuizScore `json:"score"`
+}
+
+type pendingQuestion struct {
+	session   string
+	expected  float64
+	tolerance float64
+	created   time.Time
+}
+
+// QuizStore generates and grades exercises, keeping pending questions and
+// per-session scores in memory.
+type QuizStore struct {
+	uc        *converter.UnitConverter
+	mu        sync.Mutex
+	questions map[string]pendingQuestion
+	scores    map[string]*QuizScore
+}
+
+// Errors returned by the quiz.
+var (
+	ErrQuestionNotFound = errors.New("question not found or already answered")
+	ErrNoQuizUnits      = errors.New("not enough units in this dimension for a quiz")
+)
+
+// NewQuizStore returns an empty quiz store drawing units from uc.
+func NewQuizStore(uc *converter.UnitConverter) *QuizStore {
+	return &QuizStore{
+		uc:        uc,
+		questions: make(map[string]pendingQuestion),
+		scores:    make(map[string]*QuizScore),
+	}
+}
+
+// quizUnits returns the units of a dimension suitable for exercises:
+// factor/offset units only, and for easy questions SI and imperial units
+// when the dimension has at least two of them.
+func (q *QuizStore) quizUnits(dimension string, level quizLevel) []string {
+	units := q.uc.GetUnitsByDimension(dimension)
+	var all, common []string
+	for _, symbol := range slices.Sorted(maps.Keys(units)) {
+		unit := units[symbol]
+		if unit.Conversion != nil {
+			continue
+		}
+		all = append(all, symbol)
+		if unit.System == converter.SystemSI || unit.System == converter.SystemImperial {
+			common = append(common, symbol)
+		}
+	}
+	if level.commonOnly && len(common) >= 2 {
+		return common
+	}
+	return all
+}
+
+// New generates a question for a session. An empty dimension picks one at
+// random.
+func (q *QuizStore) New(session, dimension, difficulty string, now time.Time) (QuizQuestion, error) {
+	level, ok := quizLevels[difficulty]
+	if !ok {
+		return QuizQuestion{}, fmt.Errorf("unknown difficulty %q (use easy, medium or hard)", difficulty)
+	}
+	var symbols []string
+	if dimension == "" {
+		// Pick among the dimensions that have enough units
+		var candidates []string
+		for _, dim := range q.uc.GetAllDimensions() {
+			if len(q.quizUnits(dim, level)) >= 2 {
+				candidates = append(candidates, dim)
+			}
+		}
+		if len(candidates) == 0 {
+			return QuizQuestion{}, ErrNoQuizUnits
+		}
+		dimension = candidates[rand.IntN(len(candidates))]
+	}
+	if symbols = q.quizUnits(dimension, level); len(symbols) < 2 {
+		return QuizQuestion{}, ErrNoQuizUnits
+	}
+
+	from := symbols[rand.IntN(len(symbols))]
+	to := from
+	for to == from {
+		to = symbols[rand.IntN(len(symbols))]
+	}
+	scale := math.Pow10(level.decimals)
+	value := math.Round((level.minValue+rand.Float64()*(level.maxValue-level.minValue))*scale) / scale
+	expected, err := q.uc.Convert(value, from, to)
+	if err != nil {
+		return QuizQuestion{}, err
+	}
+
+	id := randomHex(8)
+	q.mu.Lock()
+	q.questions[id] = pendingQuestion{session: session, expected: expected, tolerance: level.tolerance, created: now}
+	q.mu.Unlock()
+
+	return QuizQuestion{
+		ID:         id,
+		Question:   fmt.Sprintf("Convert %s %s to %s", strconv.FormatFloat(value, 'f', -1, 64), from, to),
+		Value:      value,
+		From:       from,
+		To:         to,
+		Dimension:  dimension,
+		Difficulty: difficulty,
+		Tolerance:  level.tolerance,
+	}, nil
+}
+
+// Answer grades an answer to one of the session's questions. Each
+// question can be answered once.
+func (q *QuizStore) Answer(session, id string, answer float64, now time.Time) (QuizResult, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending, ok := q.questions[id]
+	if !ok || pending.session != session || now.Sub(pending.created) > quizTTL {
+		return QuizResult{}, ErrQuestionNotFound
+	}
+	delete(q.questions, id)
+
+	allowed := math.Max(pending.tolerance*math.Abs(pending.expected), 1e-9)
+	correct := math.Abs(answer-pending.expected) <= allowed
+
+	score, ok := q.scores[session]
+	if !ok {
+		score = &QuizScore{}
+		q.scores[session] = score
+	}
+	score.Answered++
+	score.lastSeen = now
+	if correct {
+		score.Correct++
+		score.Streak++
+		score.BestStreak = max(score.BestStreak, score.Streak)
+	} else {
+		score.Streak = 0
+	}
+	return QuizResult{Correct: correct, Answer: answer, Expected: pending.expected, Score: *score}, nil
+}
+
+// Score returns a session's progress.
+func (q *QuizStore) Score(session string) QuizScore {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if score, ok := q.scores[session]; ok {
+		return *score
+	}
+	return QuizScore{}
+}
+
+// Prune drops expired questions, and the scores of sessions idle for as
+// long as their session cookie lives.
+func (q *QuizStore) Prune(now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for id, pending := range q.questions {
+		if now.Sub(pending.created) > quizTTL {
+			delete(q.questions, id)
+		}
+	}
+	for session, score := range q.scores {
+		if now.Sub(score.lastSeen) > historyTTL {
+			delete(q.scores, session)
+		}
+	}
+}
+
+// Handler for the quiz endpoints: POST /api/quiz/new, POST /api/quiz/answer
+// and GET /api/quiz/score
+func quizHandler(quiz *QuizStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+		}
+
+		action := r.URL.Path[len("/api/quiz/"):]
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			fail(status, message)
+			return
+		}
+		session := historySessionID(w, r, true)
+
+		switch action {
+		case "new":
+			question, err := quiz.New(session, r.FormValue("dimension"), formValueOr(r, "difficulty", "easy"), time.Now())
+			if err != nil {
+				fail(http.StatusBadRequest, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, question)
+		case "answer":
+			answer, _, err := parseQuantity(r, "answer", false)
+			if err != nil {
+				fail(http.StatusBadRequest, err.Error())
+				return
+			}
+			result, err := quiz.Answer(session, r.FormValue("id"), answer, time.Now())
+			if errors.Is(err, ErrQuestionNotFound) {
+				fail(http.StatusNotFound, err.Error())
+				return
+			}
+			writeJSON(w, http.StatusOK, result)
+		case "score":
+			writeJSON(w, http.StatusOK, quiz.Score(session))
+		default:
+			fail(http.StatusNotFound, "Unknown quiz action")
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// RangeResult is the response of the range endpoint.
+type RangeResult struct {
+	Success   bool                   `json:"success"`
+	Error     string                 `json:"error,omitempty"`
+	From      string                 `json:"from,omitempty"`
+	To        string                 `json:"to,omitempty"`
+	Min       *converter.RangePoint  `json:"min,omitempty"`
+	Max       *converter.RangePoint  `json:"max,omitempty"`
+	Formatted string                 `json:"formatted,omitempty"`
+	Series    []converter.RangePoint `json:"series,omitempty"`
+}
+
+// Handler for the range conversion endpoint
+func rangeHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, RangeResult{Success: false, Error: message})
+		}
+
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			fail(status, message)
+			return
+		}
+
+		from, to := r.Form.Get("from"), r.Form.Get("to")
+		if from == "" || to == "" || r.Form.Get("min") == "" || r.Form.Get("max") == "" {
+			fail(http.StatusBadRequest, "All fields (min, max, from, to) are required")
+			return
+		}
+		var bounds [3]float64
+		for i, name := range []string{"min", "max", "step"} {
+			raw := r.Form.Get(name)
+			if raw == "" {
+				continue
+			}
+			value, err := converter.ParseNumber(raw, requestDecimalComma(r))
+			if err != nil {
+				fail(http.StatusBadRequest, "Invalid "+name+": "+raw+" is not a number")
+				return
+			}
+			bounds[i] = value
+		}
+		notation, err := converter.ParseNotation(r.Form.Get("notation"))
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		if bounds[2] < 0 {
+			fail(http.StatusBadRequest, "Step must be positive")
+			return
+		}
+
+		lo, hi, series, err := uc.ConvertRange(r.Context(), bounds[0], bounds[1], bounds[2], from, to)
+		if err != nil {
+			// Every failure here stems from the request: bad units or bounds
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if r.Form.Get("format") == "xlsx" {
+			if series == nil {
+				series = []converter.RangePoint{lo}
+				if hi != lo {
+					series = append(series, hi)
+				}
+			}
+			cells := make([][]float64, len(series))
+			for i, point := range series {
+				cells[i] = []float64{point.Value, point.Result}
+			}
+			columns := []xlsxColumn{xlsxUnitColumn(uc, from), xlsxUnitColumn(uc, to)}
+			serveXLSX(w, "conversion-table.xlsx", "Conversions", columns, cells)
+			return
+		}
+
+		unit := uc.Resolve(to).Symbol()
+		writeJSON(w, http.StatusOK, RangeResult{
+			Success:   true,
+			From:      from,
+			To:        to,
+			Min:       &lo,
+			Max:       &hi,
+			Formatted: uc.FormatResultAs(lo.Result, unit, notation) + " – " + uc.FormatResultAs(hi.Result, unit, notation),
+			Series:    series,
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Router dispatches requests by path through a ServeMux and answers for the
+// methods every route accepts: OPTIONS lists them in an Allow header, HEAD
+// is served by the GET handler (net/http drops the body), and any other
+// method gets a JSON 405. Handlers therefore never check the method
+// themselves unless they serve several.
+type Router struct {
+	mux     *http.ServeMux
+	methods map[string][]string // pattern -> accepted methods, HEAD and OPTIONS implied
+}
+
+// NewRouter returns an empty router.
+func NewRouter() *Router {
+	return &Router{mux: http.NewServeMux(), methods: make(map[string][]string)}
+}
+
+// Handle registers handler for pattern, accepting the given methods.
+// Patterns follow http.ServeMux but must not include a method.
+func (rt *Router) Handle(pattern string, handler http.Handler, methods ...string) {
+	if len(methods) == 0 {
+		panic("routing: no methods for " + pattern)
+	}
+	rt.mux.Handle(pattern, handler)
+	rt.methods[pattern] = methods
+}
+
+// HandleFunc registers handler for pattern, accepting the given methods.
+func (rt *Router) HandleFunc(pattern string, handler http.HandlerFunc, methods ...string) {
+	rt.Handle(pattern, handler, methods...)
+}
+
+// Handles reports whether a route matches the path of r, whatever its method.
+func (rt *Router) Handles(r *http.Request) bool {
+	_, pattern := rt.mux.Handler(r)
+	_, ok := rt.methods[pattern]
+	return ok
+}
+
+// ServeHTTP routes r, answering OPTIONS and refused methods itself.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_, pattern := rt.mux.Handler(r)
+	methods, ok := rt.methods[pattern]
+	if !ok {
+		// Not found, or a redirect to the canonical path
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+
+	switch {
+	case slices.Contains(methods, r.Method),
+		r.Method == http.MethodHead && slices.Contains(methods, http.MethodGet):
+		rt.mux.ServeHTTP(w, r)
+	case r.Method == http.MethodOptions:
+		w.Header().Set("Allow", strings.Join(allowedMethods(methods), ", "))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		allowed := allowedMethods(methods)
+		w.Header().Set("Allow", strings.Join(allowed, ", "))
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{
+			"success": false,
+			"error":   "Method not allowed. Please use " + joinOr(methods) + ".",
+			"allowed": allowed,
+		})
+	}
+}
+
+// allowedMethods is the Allow header list for a route accepting methods:
+// HEAD comes with GET, and OPTIONS is always answered.
+func allowedMethods(methods []string) []string {
+	allowed := slices.Clone(methods)
+	if slices.Contains(methods, http.MethodGet) && !slices.Contains(methods, http.MethodHead) {
+		allowed = append(allowed, http.MethodHead)
+	}
+	return append(allowed, http.MethodOptions)
+}
+
+// joinOr lists methods for an error message: "GET", "GET or POST",
+// "GET, POST or DELETE".
+func joinOr(methods []string) string {
+	if len(methods) == 1 {
+		return methods[0]
+	}
+	return strings.Join(methods[:len(methods)-1], ", ") + " or " + methods[len(methods)-1]
+}
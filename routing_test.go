@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// testRouter serves /get (GET), /post (POST) and /both (GET and POST),
+// each answering with its method.
+func testRouter() *Router {
+	echo := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+		io.WriteString(w, "handled "+r.Method)
+	}
+	rt := NewRouter()
+	rt.HandleFunc("/get", echo, http.MethodGet)
+	rt.HandleFunc("/post", echo, http.MethodPost)
+	rt.HandleFunc("/both", echo, http.MethodGet, http.MethodPost)
+	return rt
+}
+
+func TestRouterAllowedMethods(t *testing.T) {
+	rt := testRouter()
+	tests := []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/get"},
+		{http.MethodPost, "/post"},
+		{http.MethodGet, "/both"},
+		{http.MethodPost, "/both"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != http.StatusOK || rec.Header().Get("X-Method") != tt.method {
+			t.Errorf("%s %s: status %d, handled as %q", tt.method, tt.path, rec.Code, rec.Header().Get("X-Method"))
+		}
+	}
+}
+
+func TestRouterOptions(t *testing.T) {
+	rt := testRouter()
+	tests := []struct {
+		path, allow string
+	}{
+		{"/get", "GET, HEAD, OPTIONS"},
+		{"/post", "POST, OPTIONS"},
+		{"/both", "GET, POST, HEAD, OPTIONS"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, tt.path, nil))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("OPTIONS %s: status %d, want 204", tt.path, rec.Code)
+		}
+		if got := rec.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("OPTIONS %s: Allow %q, want %q", tt.path, got, tt.allow)
+		}
+		if rec.Header().Get("X-Method") != "" {
+			t.Errorf("OPTIONS %s reached the handler", tt.path)
+		}
+	}
+}
+
+func TestRouterHead(t *testing.T) {
+	srv := httptest.NewServer(testRouter())
+	defer srv.Close()
+
+	for _, tt := range []struct {
+		path   string
+		status int
+	}{
+		{"/get", http.StatusOK},
+		{"/both", http.StatusOK},
+		{"/post", http.StatusMethodNotAllowed},
+	} {
+		resp, err := http.Head(srv.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("HEAD %s: status %d, want %d", tt.path, resp.StatusCode, tt.status)
+		}
+		if len(body) != 0 {
+			t.Errorf("HEAD %s: got a body %q", tt.path, body)
+		}
+		if tt.status == http.StatusOK && resp.Header.Get("X-Method") != http.MethodHead {
+			t.Errorf("HEAD %s: not served by the GET handler", tt.path)
+		}
+	}
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	rt := testRouter()
+	tests := []struct {
+		method, path string
+		allow        string
+		message      string
+		allowed      []string
+	}{
+		{http.MethodPost, "/get", "GET, HEAD, OPTIONS", "Method not allowed. Please use GET.", []string{"GET", "HEAD", "OPTIONS"}},
+		{http.MethodGet, "/post", "POST, OPTIONS", "Method not allowed. Please use POST.", []string{"POST", "OPTIONS"}},
+		{http.MethodDelete, "/both", "GET, POST, HEAD, OPTIONS", "Method not allowed. Please use GET or POST.", []string{"GET", "POST", "HEAD", "OPTIONS"}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s %s: status %d, want 405", tt.method, tt.path, rec.Code)
+			continue
+		}
+		if got := rec.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: Allow %q, want %q", tt.method, tt.path, got, tt.allow)
+		}
+		var body struct {
+			Success bool     `json:"success"`
+			Error   string   `json:"error"`
+			Allowed []string `json:"allowed"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", tt.method, tt.path, rec.Body.String(), err)
+		}
+		if body.Success || body.Error != tt.message || !reflect.DeepEqual(body.Allowed, tt.allowed) {
+			t.Errorf("%s %s: body %+v", tt.method, tt.path, body)
+		}
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	rt := testRouter()
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	if rt.Handles(req) {
+		t.Error("Handles(/missing) = true")
+	}
+	rt.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("GET /missing: status %d, want 404", rec.Code)
+	}
+}
+
+func TestMCPStreamHead(t *testing.T) {
+	transport := newMCPSSE(nil)
+	rt := NewRouter()
+	rt.HandleFunc("/mcp/sse", transport.streamHandler, http.MethodGet)
+	srv := httptest.NewServer(rt)
+	defer srv.Close()
+
+	resp, err := http.Head(srv.URL + "/mcp/sse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("HEAD /mcp/sse: status %d, Content-Type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if len(transport.sessions) != 0 {
+		t.Errorf("HEAD /mcp/sse opened %d sessions", len(transport.sessions))
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job is a periodic background task run by the Scheduler.
+type Job struct {
+	Name     string
+	Interval time.Duration                   // Time between the end of one run and the start of the next
+	Jitter   time.Duration                   // Up to this much random delay is added to each interval
+	Run      func(ctx context.Context) error // The work itself; errors are recorded in the job status
+}
+
+// JobStatus reports the state of a scheduled job.
+type JobStatus struct {
+	Name         string     `json:"name"`
+	Interval     string     `json:"interval"`
+	Jitter       string     `json:"jitter"`
+	Running      bool       `json:"running"`
+	Runs         int64      `json:"runs"`
+	Failures     int64      `json:"failures"`
+	LastRun      *time.Time `json:"lastRun,omitempty"`
+	LastDuration string     `json:"lastDuration,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	NextRun      *time.Time `json:"nextRun,omitempty"`
+}
+
+// scheduledJob pairs a job with its mutable status.
+type scheduledJob struct {
+	job    Job
+	status JobStatus
+}
+
+// Scheduler runs registered jobs periodically in the background.
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    map[string]*scheduledJob
+	started bool
+}
+
+// NewScheduler returns a scheduler with no jobs.
+func NewScheduler() *Scheduler {
+	return &Scheduler{jobs: make(map[string]*scheduledJob)}
+}
+
+// Add registers a job. Jobs must be added before Start and names must be unique.
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		panic("scheduler: Add called after Start")
+	}
+	if _, dup := s.jobs[job.Name]; dup {
+		panic("scheduler: duplicate job " + job.Name)
+	}
+	if job.Interval <= 0 {
+		panic("scheduler: job " + job.Name + " has no interval")
+	}
+	s.jobs[job.Name] = &scheduledJob{
+		job: job,
+		status: JobStatus{
+			Name:     job.Name,
+			Interval: job.Interval.String(),
+			Jitter:   job.Jitter.String(),
+		},
+	}
+}
+
+// Start launches every job. Jobs stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = true
+	for _, sj := range s.jobs {
+		go s.loop(ctx, sj)
+	}
+}
+
+// loop waits out the job's interval and runs it until ctx is done.
+func (s *Scheduler) loop(ctx context.Context, sj *scheduledJob) {
+	for {
+		wait := sj.job.Interval
+		if sj.job.Jitter > 0 {
+			wait += rand.N(sj.job.Jitter)
+		}
+		next := time.Now().Add(wait)
+		s.mu.Lock()
+		sj.status.NextRun = &next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		s.run(ctx, sj)
+	}
+}
+
+// run executes the job once and records the outcome.
+func (s *Scheduler) run(ctx context.Context, sj *scheduledJob) {
+	start := time.Now()
+	s.mu.Lock()
+	sj.status.Running = true
+	sj.status.NextRun = nil
+	s.mu.Unlock()
+
+	err := func() (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = fmt.Errorf("panic: %v", p)
+			}
+		}()
+		return sj.job.Run(ctx)
+	}()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sj.status.Running = false
+	sj.status.Runs++
+	sj.status.LastRun = &start
+	sj.status.LastDuration = time.Since(start).String()
+	sj.status.LastError = ""
+	if err != nil {
+		sj.status.Failures++
+		sj.status.LastError = err.Error()
+		log.Printf("Job %s failed: %v", sj.job.Name, err)
+	}
+}
+
+// Status returns the status of every job, sorted by name.
+func (s *Scheduler) Status() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, sj := range s.jobs {
+		statuses = append(statuses, sj.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Handler for the job status endpoint
+func jobsHandler(s *Scheduler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, s.Status())
+	}
+}
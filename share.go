@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+const (
+	shareIDLength = 6
+	shareTTL      = 90 * 24 * time.Hour
+	maxShares     = 100000
+	shareAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// SharedConversion is a conversion saved behind a short link.
+type SharedConversion struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace,omitempty"` // Registry the units belong to; empty for the global one
+	Value     float64   `json:"value"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Precision int       `json:"precision"` // -1 for the default formatting
+	Result    float64   `json:"result"`
+	Formatted string    `json:"formatted"`
+	Created   time.Time `json:"created"`
+}
+
+// Summary is the one-line form of the conversion, such as "10 km = 6.214 mi".
+func (s SharedConversion) Summary() string {
+	return converter.WithUnit(strconv.FormatFloat(s.Value, 'f', -1, 64), s.From) + " = " + s.Formatted
+}
+
+// Errors returned by the share store.
+var (
+	ErrShareNotFound = errors.New("short link not found")
+	ErrTooManyShares = errors.New("too many short links, try again later")
+)
+
+// ShareStore keeps shared conversions in memory, by short link ID. One
+// store holds the links of every namespace.
+type ShareStore struct {
+	mu     sync.Mutex
+	shares map[string]SharedConversion
+}
+
+// NewShareStore returns an empty share store.
+func NewShareStore() *ShareStore {
+	return &ShareStore{shares: make(map[string]SharedConversion)}
+}
+
+// newShareID returns a random short link ID.
+func newShareID() string {
+	b := make([]byte, shareIDLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = shareAlphabet[int(b[i])%len(shareAlphabet)]
+	}
+	return string(b)
+}
+
+// Share converts value with uc and saves the result behind a new short
+// link. The result is stored, so the link keeps showing it after units are
+// reloaded.
+func (s *ShareStore) Share(ctx context.Context, uc *converter.UnitConverter, value float64, from, to string, precision int, now time.Time) (SharedConversion, error) {
+	result, err := uc.ConvertContext(ctx, value, from, to)
+	if err != nil {
+		return SharedConversion{}, err
+	}
+	shared := SharedConversion{
+		Namespace: uc.Namespace(),
+		Value:     value,
+		From:      uc.Resolve(from).Symbol(),
+		To:        uc.Resolve(to).Symbol(),
+		Precision: precision,
+		Result:    result,
+		Created:   now,
+	}
+	shared.Formatted = formatPlaces(uc, result, shared.From, shared.To, precision)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.shares) >= maxShares {
+		return SharedConversion{}, ErrTooManyShares
+	}
+	for {
+		shared.ID = newShareID()
+		if _, taken := s.shares[shared.ID]; !taken {
+			break
+		}
+	}
+	s.shares[shared.ID] = shared
+	return shared, nil
+}
+
+// Get returns the conversion behind a short link shared in namespace.
+func (s *ShareStore) Get(id, namespace string, now time.Time) (SharedConversion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	shared, ok := s.shares[id]
+	if !ok || shared.Namespace != namespace || now.Sub(shared.Created) > shareTTL {
+		return SharedConversion{}, ErrShareNotFound
+	}
+	return shared, nil
+}
+
+// Prune drops short links older than shareTTL.
+func (s *ShareStore) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, shared := range s.shares {
+		if now.Sub(shared.Created) > shareTTL {
+			delete(s.shares, id)
+		}
+	}
+}
+
+// sharePath returns the path of a short link, under the namespace prefix
+// for links shared in a namespace so they open against its registry.
+func sharePath(shared SharedConversion) string {
+	if shared.Namespace == "" {
+		return "/s/" + shared.ID
+	}
+	return namespacePrefix + shared.Namespace + "/s/" + shared.ID
+}
+
+// absoluteURL returns path as an absolute URL on the host that received r,
+// as link previews need absolute URLs. path is relative to the base path.
+func absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + BasePath(r.Context()) + path
+}
+
+// Handler for the share endpoint, POST /api/share
+func shareHandler(uc *converter.UnitConverter, shares *ShareStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fail := func(status int, message string) {
+			writeJSON(w, status, map[string]interface{}{"success": false, "error": message})
+		}
+
+		if err := r.ParseForm(); err != nil {
+			status, message := bodyError(err, "Error parsing form data")
+			fail(status, message)
+			return
+		}
+		from, to := r.FormValue("from"), r.FormValue("to")
+		if r.FormValue("value") == "" || from == "" || to == "" {
+			fail(http.StatusBadRequest, "All fields (value, from, to) are required")
+			return
+		}
+		value, _, err := parseQuantity(r, "value", false)
+		if err != nil {
+			fail(http.StatusBadRequest, err.Error())
+			return
+		}
+		precision := -1
+		if raw := r.FormValue("precision"); raw != "" {
+			if precision, err = converter.ParsePrecision(raw); err != nil {
+				fail(http.StatusBadRequest, err.Error())
+				return
+			}
+		}
+
+		shared, err := shares.Share(r.Context(), uc, value, from, to, precision, time.Now())
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, ErrTooManyShares) {
+				status = http.StatusServiceUnavailable
+			}
+			fail(status, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusCreated, map[string]interface{}{
+			"success":   true,
+			"id":        shared.ID,
+			"url":       absoluteURL(r, sharePath(shared)),
+			"result":    shared.Result,
+			"formatted": shared.Formatted,
+		})
+	}
+}
+
+// SharePageData is passed to the share.html template.
+type SharePageData struct {
+	Shared      SharedConversion
+	Title       string
+	Description string
+	URL         string
+}
+
+// Handler for short links, /s/{id}: the shared result with Open Graph tags
+// so the link unfurls in chat apps
+func sharePageHandler(uc *converter.UnitConverter, shares *ShareStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/s/")
+		shared, err := shares.Get(id, uc.Namespace(), time.Now())
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		from, to := uc.Resolve(shared.From), uc.Resolve(shared.To)
+		description := "Converted with goverter"
+		if from.Valid() && to.Valid() {
+			description = "Convert " + from.Unit().Name + " to " + to.Unit().Name + " with goverter"
+		}
+		data := SharePageData{
+			Shared:      shared,
+			Title:       shared.Summary(),
+			Description: description,
+			URL:         absoluteURL(r, sharePath(shared)),
+		}
+
+		tmpl, err := template.New("share.html").Funcs(pageFuncs(r)).ParseFiles("templates/share.html")
+		if err != nil {
+			http.Error(w, "Error loading template: "+err.Error(), http.StatusInternalServerError)
+			log.Printf("Error loading template: %v", err)
+			return
+		}
+		// Shared results never change, so caches may keep them
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		if err := tmpl.Execute(w, data); err != nil {
+			http.Error(w, "Error rendering template", http.StatusInternalServerError)
+			log.Printf("Error rendering template: %v", err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// slackMaxSkew is how old a Slack request timestamp may be before the
+// request is rejected as a possible replay.
+const slackMaxSkew = 5 * time.Minute
+
+const slackUsage = "Usage: `/convert 5 kg to lb` (also `in`, `as` or `->` between the units)"
+
+// slackMessage is a slash-command response.
+type slackMessage struct {
+	ResponseType string `json:"response_type"` // "in_channel" or "ephemeral"
+	Text         string `json:"text"`
+}
+
+// verifySlackSignature checks the v0 request signature Slack sends with
+// every slash command.
+func verifySlackSignature(secret string, header http.Header, body []byte, now time.Time) bool {
+	ts, err := strconv.ParseInt(header.Get("X-Slack-Request-Timestamp"), 10, 64)
+	if err != nil {
+		return false
+	}
+	if skew := now.Sub(time.Unix(ts, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%d:", ts)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature")))
+}
+
+// slackReply answers the text of a /convert command.
+func slackReply(ctx context.Context, uc *converter.UnitConverter, text string) slackMessage {
+	text = strings.TrimSpace(text)
+	if text == "" || text == "help" {
+		return slackMessage{ResponseType: "ephemeral", Text: slackUsage}
+	}
+
+	if _, err := converter.ParseExpression(text); err != nil {
+		return slackMessage{ResponseType: "ephemeral", Text: "Sorry, I couldn't read that. " + slackUsage}
+	}
+
+	expr, result, err := uc.EvaluateContext(ctx, text)
+	var unknown *converter.UnknownUnitError
+	if errors.As(err, &unknown) {
+		return slackMessage{ResponseType: "ephemeral", Text: slackUnknownUnitHint(uc, unknown)}
+	}
+	if err != nil {
+		return slackMessage{ResponseType: "ephemeral", Text: "Sorry, " + err.Error()}
+	}
+	return slackMessage{
+		ResponseType: "in_channel",
+		Text:         fmt.Sprintf("%s = *%s*", converter.WithUnit(fmt.Sprintf("%g", expr.Value), expr.From), uc.FormatResult(result, expr.To)),
+	}
+}
+
+// slackUnknownUnitHint explains an unknown unit, offering close matches
+// or, failing that, units whose name contains what was typed.
+func slackUnknownUnitHint(uc *converter.UnitConverter, unknown *converter.UnknownUnitError) string {
+	hint := fmt.Sprintf("I don't know the unit `%s`.", unknown.Symbol)
+	symbols := unknown.Suggestions
+	if len(symbols) == 0 {
+		for _, m := range uc.SearchUnits(unknown.Symbol, "") {
+			symbols = append(symbols, m.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return hint + " Units are written by symbol, like `kg`, `mi` or `C`."
+	}
+	if len(symbols) > 5 {
+		symbols = symbols[:5]
+	}
+
+	units := uc.Snapshot().Units()
+	options := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		options[i] = fmt.Sprintf("`%s` (%s)", symbol, units[symbol].Name)
+	}
+	return hint + " Did you mean " + strings.Join(options, ", ") + "?"
+}
+
+// Handler for the Slack slash-command endpoint
+func slackHandler(uc *converter.UnitConverter, signingSecret string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if signingSecret == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			status, message := bodyError(err, "Error reading request body")
+			http.Error(w, message, status)
+			return
+		}
+		if !verifySlackSignature(signingSecret, r.Header, body, time.Now()) {
+			http.Error(w, "Invalid Slack signature", http.StatusUnauthorized)
+			return
+		}
+
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			http.Error(w, "Error parsing form data", http.StatusBadRequest)
+			return
+		}
+
+		// Slack shows anything but a 200 as a failure, so errors are replies too
+		writeJSON(w, http.StatusOK, slackReply(r.Context(), uc, form.Get("text")))
+	}
+}
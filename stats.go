@@ -0,0 +1,213 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// statsWindow is how far back the per-hour conversion counts go.
+const statsWindow = 24 * time.Hour
+
+// maxTopConversions is the length of the popularity ranking, and so the
+// most pairs /api/stats lists.
+const maxTopConversions = 100
+
+// maxTrackedPairs bounds the pairs counted. Each ranking drops the least
+// used beyond it, and new pairs aren't counted past twice as many.
+const maxTrackedPairs = 10000
+
+// conversionPair identifies a conversion direction within a namespace,
+// e.g. kg -> lb. The global registry is the namespace "".
+type conversionPair struct {
+	Namespace string
+	From      string
+	To        string
+}
+
+// ConversionStats aggregates conversion usage in memory. Counts are
+// updated on every conversion, while the popularity ranking is recomputed
+// periodically by the stats-popularity job.
+type ConversionStats struct {
+	mu         sync.Mutex
+	pairs      map[conversionPair]int64
+	converters map[string]*converter.UnitConverter // Namespace -> its registry, to resolve pairs
+	dimensions map[string]int64
+	hourly     map[int64]int64 // Unix hour -> successful conversions
+	total      int64
+	errors     int64
+	ranking    []PairCount // Most used pairs, most used first
+	rankedAt   time.Time   // When ranking was last recomputed
+}
+
+// PairCount is the usage count of a single conversion direction.
+type PairCount struct {
+	Namespace string `json:"namespace,omitempty"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Dimension string `json:"dimension"`
+	Count     int64  `json:"count"`
+}
+
+// HourCount is the number of conversions performed during one hour.
+type HourCount struct {
+	Hour  time.Time `json:"hour"`
+	Count int64     `json:"count"`
+}
+
+// StatsSnapshot is a point-in-time view of the conversion statistics.
+type StatsSnapshot struct {
+	Total          int64            `json:"total"`
+	Errors         int64            `json:"errors"`
+	ErrorRate      float64          `json:"errorRate"`
+	TopConversions []PairCount      `json:"topConversions"`
+	RankedAt       *time.Time       `json:"rankedAt,omitempty"` // When topConversions was computed
+	Dimensions     map[string]int64 `json:"dimensions"`
+	PerHour        []HourCount      `json:"perHour"`
+}
+
+// NewConversionStats returns an empty statistics aggregator.
+func NewConversionStats() *ConversionStats {
+	return &ConversionStats{
+		pairs:      make(map[conversionPair]int64),
+		converters: make(map[string]*converter.UnitConverter),
+		dimensions: make(map[string]int64),
+		hourly:     make(map[int64]int64),
+	}
+}
+
+// RecordSuccess counts a successful conversion made with uc, the global
+// registry or a namespace's.
+func (s *ConversionStats) RecordSuccess(uc *converter.UnitConverter, from, to, dimension string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	pair := conversionPair{Namespace: uc.Namespace(), From: from, To: to}
+	if _, ok := s.pairs[pair]; ok || len(s.pairs) < 2*maxTrackedPairs {
+		s.pairs[pair]++
+		s.converters[pair.Namespace] = uc
+	}
+	s.dimensions[dimension]++
+	s.hourly[at.Unix()/3600]++
+}
+
+// RecordError counts a conversion request that failed.
+func (s *ConversionStats) RecordError() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total++
+	s.errors++
+}
+
+// Prune drops hourly buckets that fell out of the stats window.
+func (s *ConversionStats) Prune(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	oldest := now.Add(-statsWindow).Unix() / 3600
+	for hour := range s.hourly {
+		if hour <= oldest {
+			delete(s.hourly, hour)
+		}
+	}
+}
+
+// RecomputePopularity ranks the conversion pairs by use, keeping the
+// most used, and stops counting the least used beyond maxTrackedPairs.
+// Pair dimensions are resolved against the registry of the namespace each
+// pair was recorded in, so renamed units don't linger.
+func (s *ConversionStats) RecomputePopularity(now time.Time) {
+	s.mu.Lock()
+	pairs := make([]PairCount, 0, len(s.pairs))
+	for pair, count := range s.pairs {
+		pairs = append(pairs, PairCount{Namespace: pair.Namespace, From: pair.From, To: pair.To, Count: count})
+	}
+	converters := make(map[string]*converter.UnitConverter, len(s.converters))
+	for namespace, uc := range s.converters {
+		converters[namespace] = uc
+	}
+	s.mu.Unlock()
+
+	sort.Slice(pairs, func(i, j int) bool {
+		a, b := pairs[i], pairs[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.From+a.To < b.From+b.To
+	})
+	ranking := append([]PairCount{}, pairs[:min(len(pairs), maxTopConversions)]...)
+	units := make(map[string]map[string]converter.Unit)
+	for i, pair := range ranking {
+		if _, ok := units[pair.Namespace]; !ok {
+			units[pair.Namespace] = converters[pair.Namespace].Snapshot().Units()
+		}
+		ranking[i].Dimension = units[pair.Namespace][pair.From].Dimension
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ranking, s.rankedAt = ranking, now.UTC()
+	if len(pairs) > maxTrackedPairs {
+		for _, pair := range pairs[maxTrackedPairs:] {
+			delete(s.pairs, conversionPair{Namespace: pair.Namespace, From: pair.From, To: pair.To})
+		}
+	}
+}
+
+// Snapshot returns the current statistics with at most top pairs listed
+// from the last popularity ranking.
+func (s *ConversionStats) Snapshot(top int, now time.Time) StatsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snap := StatsSnapshot{
+		Total:          s.total,
+		Errors:         s.errors,
+		TopConversions: append([]PairCount{}, s.ranking[:min(top, len(s.ranking))]...),
+		Dimensions:     make(map[string]int64, len(s.dimensions)),
+		PerHour:        make([]HourCount, 0, 24),
+	}
+	if s.total > 0 {
+		snap.ErrorRate = float64(s.errors) / float64(s.total)
+	}
+	if !s.rankedAt.IsZero() {
+		rankedAt := s.rankedAt
+		snap.RankedAt = &rankedAt
+	}
+
+	for dim, count := range s.dimensions {
+		snap.Dimensions[dim] = count
+	}
+
+	current := now.Unix() / 3600
+	for hour := current - 23; hour <= current; hour++ {
+		snap.PerHour = append(snap.PerHour, HourCount{
+			Hour:  time.Unix(hour*3600, 0).UTC(),
+			Count: s.hourly[hour],
+		})
+	}
+	return snap
+}
+
+// Handler for the statistics endpoint
+func statsHandler(stats *ConversionStats) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		top := 10
+		if v := r.URL.Query().Get("top"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 || n > maxTopConversions {
+				http.Error(w, "top must be between 1 and "+strconv.Itoa(maxTopConversions), http.StatusBadRequest)
+				return
+			}
+			top = n
+		}
+
+		writeJSON(w, http.StatusOK, stats.Snapshot(top, time.Now()))
+	}
+}
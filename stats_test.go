@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+func TestRecomputePopularity(t *testing.T) {
+	uc := converter.NewUnitConverter()
+	path := filepath.Join(t.TempDir(), "acme.json")
+	content := `{"units": {"pallet": {"factor": 500000, "dimension": "mass", "name": "Pallet"}}}`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	acme, err := uc.NewNamespace("acme", []string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := NewConversionStats()
+	now := time.Now()
+	for range 3 {
+		stats.RecordSuccess(acme, "pallet", "kg", "mass", now)
+	}
+	for range 2 {
+		stats.RecordSuccess(uc, "m", "ft", "length", now)
+	}
+	stats.RecordSuccess(acme, "m", "ft", "length", now)
+
+	// The ranking is only updated by RecomputePopularity
+	if snap := stats.Snapshot(10, now); len(snap.TopConversions) != 0 || snap.RankedAt != nil {
+		t.Errorf("ranked before recomputing: %+v", snap.TopConversions)
+	}
+	stats.RecomputePopularity(now)
+	want := []PairCount{
+		{Namespace: "acme", From: "pallet", To: "kg", Dimension: "mass", Count: 3},
+		{From: "m", To: "ft", Dimension: "length", Count: 2},
+		{Namespace: "acme", From: "m", To: "ft", Dimension: "length", Count: 1},
+	}
+	snap := stats.Snapshot(10, now)
+	if len(snap.TopConversions) != len(want) {
+		t.Fatalf("top conversions = %+v, want %+v", snap.TopConversions, want)
+	}
+	for i := range want {
+		if snap.TopConversions[i] != want[i] {
+			t.Errorf("top conversion %d = %+v, want %+v", i, snap.TopConversions[i], want[i])
+		}
+	}
+	if snap.RankedAt == nil || !snap.RankedAt.Equal(now) {
+		t.Errorf("rankedAt = %v, want %v", snap.RankedAt, now)
+	}
+	if top := stats.Snapshot(1, now).TopConversions; len(top) != 1 {
+		t.Errorf("top 1 lists %d pairs", len(top))
+	}
+}
+
+func TestConversionStatsPairLimit(t *testing.T) {
+	uc := converter.NewUnitConverter()
+	stats := NewConversionStats()
+	now := time.Now()
+	stats.RecordSuccess(uc, "m", "ft", "length", now)
+	stats.RecordSuccess(uc, "m", "ft", "length", now)
+	for i := range 2*maxTrackedPairs + 10 {
+		stats.RecordSuccess(uc, "m", "x"+strconv.Itoa(i), "length", now)
+	}
+	if got := len(stats.pairs); got != 2*maxTrackedPairs {
+		t.Errorf("tracking %d pairs, want at most %d", got, 2*maxTrackedPairs)
+	}
+	if stats.Snapshot(1, now).Total != 2*maxTrackedPairs+12 {
+		t.Error("untracked pairs are missing from the total")
+	}
+
+	stats.RecomputePopularity(now)
+	if got := len(stats.pairs); got != maxTrackedPairs {
+		t.Errorf("tracking %d pairs after ranking, want %d", got, maxTrackedPairs)
+	}
+	if top := stats.Snapshot(1, now).TopConversions; len(top) != 1 || top[0].To != "ft" || top[0].Count != 2 {
+		t.Errorf("top conversion = %+v, want m -> ft twice", top)
+	}
+}
@@ -0,0 +1,152 @@
+// Package templatefuncs exposes goverter conversions as template functions,
+// for programs that render reports with html/template or text/template:
+//
+//	tmpl := template.New("report").Funcs(templatefuncs.FuncMap(uc))
+//
+// The functions take the piped value last, so they chain in pipelines:
+//
+//	{{.Distance | convert "km" "mi" | formatQuantity "mi"}}   → 6.214 mi
+//	{{.Visitors | humanize}}                                  → 1.2 million
+package templatefuncs
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// Converter is the part of converter.UnitConverter the template functions
+// need. html/template.FuncMap is the same type as text/template.FuncMap,
+// so the map returned by FuncMap works with both packages.
+type Converter interface {
+	Convert(value float64, from, to string) (float64, error)
+	FormatResult(result float64, unit string) string
+}
+
+// FuncMap returns the template functions bound to c:
+//
+//	convert FROM TO VALUE        the value converted, as a float64
+//	formatQuantity UNIT VALUE    the value formatted with its unit, e.g. "2.205 lb"
+//	humanize VALUE               a number for people to read, e.g. "12,345.68" or "3.4 billion"
+//
+// Values may be any Go number or a numeric string. Conversion errors stop
+// template execution like any function error.
+func FuncMap(c Converter) template.FuncMap {
+	return template.FuncMap{
+		"convert": func(from, to string, value any) (float64, error) {
+			v, err := toFloat(value)
+			if err != nil {
+				return 0, err
+			}
+			return c.Convert(v, from, to)
+		},
+		"formatQuantity": func(unit string, value any) (string, error) {
+			v, err := toFloat(value)
+			if err != nil {
+				return "", err
+			}
+			return c.FormatResult(v, unit), nil
+		},
+		"humanize": Humanize,
+	}
+}
+
+// toFloat reads a template argument as a number.
+func toFloat(value any) (float64, error) {
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int8:
+		return float64(v), nil
+	case int16:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint:
+		return float64(v), nil
+	case uint8:
+		return float64(v), nil
+	case uint16:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("templatefuncs: %q is not a number", v)
+		}
+		return f, nil
+	case fmt.Stringer:
+		// json.Number and similar
+		return toFloat(v.String())
+	}
+	return 0, fmt.Errorf("templatefuncs: cannot use %T as a number", value)
+}
+
+// largeNumbers are the names Humanize uses for large magnitudes.
+var largeNumbers = []struct {
+	value float64
+	name  string
+}{
+	{1e12, "trillion"},
+	{1e9, "billion"},
+	{1e6, "million"},
+}
+
+// Humanize formats a number for reading: millions and up by name with one
+// decimal ("3.4 billion"), other numbers with thousands separators and at
+// most two decimals ("12,345.68"), and tiny numbers with three significant
+// digits ("0.000123").
+func Humanize(value any) (string, error) {
+	v, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+	abs := math.Abs(v)
+	switch {
+	case math.IsNaN(v) || math.IsInf(v, 0):
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case abs >= 1e15:
+		return strconv.FormatFloat(v, 'g', 3, 64), nil
+	case abs >= 1e6:
+		for _, large := range largeNumbers {
+			if abs >= large.value {
+				scaled := strconv.FormatFloat(v/large.value, 'f', 1, 64)
+				return strings.TrimSuffix(scaled, ".0") + " " + large.name, nil
+			}
+		}
+	case abs != 0 && abs < 0.01:
+		return strconv.FormatFloat(v, 'g', 3, 64), nil
+	}
+	return groupThousands(strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)), nil
+}
+
+// groupThousands inserts commas into the integer part of a formatted number.
+func groupThousands(number string) string {
+	sign := ""
+	if strings.HasPrefix(number, "-") {
+		sign, number = "-", number[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(number, ".")
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		b.WriteString("." + fraction)
+	}
+	return sign + b.String()
+}
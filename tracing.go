@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+)
+
+// Trace context propagation following the W3C Trace Context format. The
+// server reads the traceparent header of incoming requests into the request
+// context; conversions that call remote services forward it with
+// InjectTraceParent, so their requests join the caller's trace.
+
+// traceparentPattern matches a version 00 traceparent header:
+// version-traceid-parentid-flags.
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+const (
+	zeroTraceID  = "00000000000000000000000000000000"
+	zeroParentID = "0000000000000000"
+)
+
+type traceParentKey struct{}
+
+// validTraceParent reports whether a traceparent header is well formed.
+// All-zero trace and parent IDs are invalid.
+func validTraceParent(traceparent string) bool {
+	m := traceparentPattern.FindStringSubmatch(traceparent)
+	return m != nil && m[1] != zeroTraceID && m[2] != zeroParentID
+}
+
+// WithTraceParent returns a context carrying a traceparent. Malformed values
+// are ignored.
+func WithTraceParent(ctx context.Context, traceparent string) context.Context {
+	if !validTraceParent(traceparent) {
+		return ctx
+	}
+	return context.WithValue(ctx, traceParentKey{}, traceparent)
+}
+
+// TraceParent returns the traceparent carried by ctx, or "".
+func TraceParent(ctx context.Context) string {
+	traceparent, _ := ctx.Value(traceParentKey{}).(string)
+	return traceparent
+}
+
+// TraceID returns the trace ID carried by ctx, or "".
+func TraceID(ctx context.Context) string {
+	if traceparent := TraceParent(ctx); traceparent != "" {
+		return traceparent[3:35]
+	}
+	return ""
+}
+
+// InjectTraceParent sets the traceparent header of an outgoing request made
+// on behalf of ctx: same trace and flags, with a new parent ID for the call.
+func InjectTraceParent(ctx context.Context, header http.Header) {
+	traceparent := TraceParent(ctx)
+	if traceparent == "" {
+		return
+	}
+	header.Set("traceparent", traceparent[:36]+randomHex(8)+traceparent[52:])
+}
+
+// traceMiddleware puts the request's traceparent into its context, starting
+// a new trace when the client didn't send one.
+func traceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent := r.Header.Get("traceparent")
+		if !validTraceParent(traceparent) {
+			traceparent = "00-" + randomHex(16) + "-" + randomHex(8) + "-00"
+		}
+		next.ServeHTTP(w, r.WithContext(WithTraceParent(r.Context(), traceparent)))
+	})
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// checkUnits builds the registry configured by cfg and that of every
+// namespace, writing a line per registry and the problems found. It
+// reports whether all of them are valid.
+func checkUnits(w io.Writer, cfg Config) bool {
+	uc := converter.NewUnitConverter()
+	uc.SetUnitPacks(cfg.UnitPacks)
+	uc.SetUnitFiles(cfg.UnitFiles)
+	if _, err := uc.Reload(); err != nil {
+		fmt.Fprintf(w, "global: %v\n", err)
+		return false
+	}
+	reg := uc.Snapshot()
+	fmt.Fprintf(w, "global: ok, %d units and %d aliases in %d dimensions\n", len(reg.Units()), len(reg.Aliases()), len(reg.Dimensions()))
+
+	namespaces, _ := parseNamespaces(cfg.Namespaces)
+	ok := true
+	for _, config := range namespaces {
+		ns, err := uc.NewNamespace(config.Name, config.Files)
+		if err != nil {
+			fmt.Fprintf(w, "%v\n", err)
+			ok = false
+			continue
+		}
+		reg := ns.Snapshot()
+		fmt.Fprintf(w, "namespace %s: ok, %d units and %d aliases in %d dimensions\n", config.Name, len(reg.Units()), len(reg.Aliases()), len(reg.Dimensions()))
+	}
+	return ok
+}
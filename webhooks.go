@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// EventCatalogUpdated is sent when units are added, changed or removed.
+const EventCatalogUpdated = "catalog.updated"
+
+// webhookEvents lists the events a webhook can subscribe to.
+var webhookEvents = map[string]bool{
+	EventCatalogUpdated: true,
+}
+
+// Webhook is a registered notification endpoint.
+type Webhook struct {
+	ID           string     `json:"id"`
+	URL          string     `json:"url"`
+	Events       []string   `json:"events"`
+	Secret       string     `json:"-"` // HMAC key for the X-Goverter-Signature header
+	CreatedAt    time.Time  `json:"createdAt"`
+	LastDelivery *time.Time `json:"lastDelivery,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+}
+
+// webhookPayload is the JSON body posted to webhooks.
+type webhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+// storedWebhook is a webhook as saved in the webhooks file, secret
+// included. Delivery status is not saved.
+type storedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDispatcher stores webhooks and delivers events to them. With a
+// file, registrations are saved to it as JSON lines and survive restarts;
+// otherwise they are kept in memory. Registering appends a line and
+// removing rewrites the file without it.
+type WebhookDispatcher struct {
+	path        string
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration // Delay before the first retry; doubled on each attempt
+
+	mu    sync.Mutex
+	file  *os.File
+	hooks map[string]*Webhook
+}
+
+// OpenWebhookDispatcher returns a dispatcher with the webhooks saved at
+// path, creating the file if needed. An empty path keeps them in memory.
+func OpenWebhookDispatcher(path string) (*WebhookDispatcher, error) {
+	d := &WebhookDispatcher{
+		path:        path,
+		hooks:       make(map[string]*Webhook),
+		client:      &http.Client{Timeout: 10 * time.Second},
+		maxAttempts: 5,
+		backoff:     time.Second,
+	}
+	if path == "" {
+		return d, nil
+	}
+	hooks, err := readWebhooksFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, hook := range hooks {
+		d.hooks[hook.ID] = hook
+	}
+	if d.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// readWebhooksFile reads the webhooks of a webhooks file, oldest first.
+func readWebhooksFile(path string) ([]*Webhook, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hooks []*Webhook
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var stored storedWebhook
+		if err := json.Unmarshal(scanner.Bytes(), &stored); err != nil {
+			return nil, fmt.Errorf("%s: line %d: %w", path, lineNo, err)
+		}
+		hook := stored.Webhook
+		hook.Secret = stored.Secret
+		hooks = append(hooks, &hook)
+	}
+	return hooks, scanner.Err()
+}
+
+// save appends hook to the webhooks file. The caller holds d.mu.
+func (d *WebhookDispatcher) save(hook *Webhook) {
+	if d.file == nil {
+		return
+	}
+	line, err := json.Marshal(storedWebhook{Webhook: *hook, Secret: hook.Secret})
+	if err != nil {
+		log.Printf("Error encoding webhook %s: %v", hook.ID, err)
+		return
+	}
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		log.Printf("Error saving webhook %s: %v", hook.ID, err)
+	}
+}
+
+// rewrite replaces the webhooks file with the current webhooks. The caller
+// holds d.mu.
+func (d *WebhookDispatcher) rewrite() error {
+	if d.file == nil {
+		return nil
+	}
+	hooks := make([]*Webhook, 0, len(d.hooks))
+	for _, hook := range d.hooks {
+		hooks = append(hooks, hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+	})
+
+	tmp := d.path + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	enc := json.NewEncoder(w)
+	for _, hook := range hooks {
+		if err := enc.Encode(storedWebhook{Webhook: *hook, Secret: hook.Secret}); err != nil {
+			out.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, d.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	d.file.Close()
+	d.file, err = os.OpenFile(d.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	return err
+}
+
+// randomHex returns n random bytes, hex encoded.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Register adds a webhook. An empty secret is replaced by a random one.
+func (d *WebhookDispatcher) Register(rawURL string, events []string, secret string) (*Webhook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL: %q", rawURL)
+	}
+	if len(events) == 0 {
+		for event := range webhookEvents {
+			events = append(events, event)
+		}
+		sort.Strings(events)
+	}
+	for _, event := range events {
+		if !webhookEvents[event] {
+			return nil, fmt.Errorf("unknown event: %s", event)
+		}
+	}
+	if secret == "" {
+		secret = randomHex(32)
+	}
+
+	hook := &Webhook{
+		ID:        randomHex(8),
+		URL:       u.String(),
+		Events:    events,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	}
+	d.mu.Lock()
+	d.hooks[hook.ID] = hook
+	d.save(hook)
+	d.mu.Unlock()
+	return hook, nil
+}
+
+// Remove deletes a webhook and reports whether it existed.
+func (d *WebhookDispatcher) Remove(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.hooks[id]; !ok {
+		return false
+	}
+	delete(d.hooks, id)
+	if err := d.rewrite(); err != nil {
+		log.Printf("Error saving webhooks after removing %s: %v", id, err)
+	}
+	return true
+}
+
+// List returns copies of all webhooks, oldest first.
+func (d *WebhookDispatcher) List() []Webhook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hooks := make([]Webhook, 0, len(d.hooks))
+	for _, hook := range d.hooks {
+		hooks = append(hooks, *hook)
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].CreatedAt.Before(hooks[j].CreatedAt)
+	})
+	return hooks
+}
+
+// Notify delivers event to every subscribed webhook in the background.
+func (d *WebhookDispatcher) Notify(event string, data interface{}) {
+	payload := webhookPayload{
+		ID:        randomHex(8),
+		Event:     event,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("Error encoding %s webhook payload: %v", event, err)
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, hook := range d.hooks {
+		for _, e := range hook.Events {
+			if e == event {
+				go d.deliver(hook.ID, hook.URL, hook.Secret, payload, body)
+				break
+			}
+		}
+	}
+}
+
+// deliver posts body to a webhook, retrying with exponential backoff.
+func (d *WebhookDispatcher) deliver(id, target, secret string, payload webhookPayload, body []byte) {
+	var err error
+	delay := d.backoff
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		if err = d.post(target, secret, payload, body); err == nil {
+			break
+		}
+		if attempt < d.maxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	now := time.Now().UTC()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	hook, ok := d.hooks[id]
+	if !ok {
+		return
+	}
+	hook.LastDelivery = &now
+	hook.LastError = ""
+	if err != nil {
+		hook.LastError = err.Error()
+		log.Printf("Webhook %s: giving up on %s delivery %s: %v", id, payload.Event, payload.ID, err)
+	}
+}
+
+// post makes a single signed delivery attempt.
+func (d *WebhookDispatcher) post(target, secret string, payload webhookPayload, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "goverter-webhooks")
+	req.Header.Set("X-Goverter-Event", payload.Event)
+	req.Header.Set("X-Goverter-Delivery", payload.ID)
+	req.Header.Set("X-Goverter-Timestamp", timestamp)
+	req.Header.Set("X-Goverter-Signature", "sha256="+signWebhook(secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// signWebhook computes the HMAC-SHA256 of "<timestamp>.<body>".
+// Receivers should recompute it and reject stale timestamps.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Handler for the webhook management endpoint
+func webhooksHandler(d *WebhookDispatcher, audit *AuditLog) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			writeJSON(w, http.StatusOK, d.List())
+
+		case http.MethodPost:
+			var req struct {
+				URL    string   `json:"url"`
+				Events []string `json:"events"`
+				Secret string   `json:"secret"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				status, message := bodyError(err, "Invalid JSON body")
+				writeJSON(w, status, map[string]interface{}{
+					"success": false,
+					"error":   message,
+				})
+				return
+			}
+			hook, err := d.Register(req.URL, req.Events, req.Secret)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+					"success": false,
+					"error":   err.Error(),
+				})
+				return
+			}
+			audit.RecordRequest(r, "admin", AuditWebhookCreate, map[string]interface{}{
+				"id":     hook.ID,
+				"url":    hook.URL,
+				"events": hook.Events,
+			})
+			// The secret is only ever returned at creation time.
+			writeJSON(w, http.StatusCreated, map[string]interface{}{
+				"success": true,
+				"webhook": hook,
+				"secret":  hook.Secret,
+			})
+
+		case http.MethodDelete:
+			id := r.URL.Query().Get("id")
+			if !d.Remove(id) {
+				writeJSON(w, http.StatusNotFound, map[string]interface{}{
+					"success": false,
+					"error":   "Unknown webhook",
+				})
+				return
+			}
+			audit.RecordRequest(r, "admin", AuditWebhookDelete, map[string]interface{}{"id": id})
+			writeJSON(w, http.StatusOK, map[string]interface{}{"success": true})
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	htmltemplate "html/template"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// widgetVersion is bumped whenever the widget script or markup changes in
+// a way embedders should pick up. It is part of every widget URL so
+// caches never mix versions.
+const widgetVersion = "1"
+
+// widgetScript is the loader third-party pages include. It replaces every
+// element marked with data-goverter-widget by an iframe showing /widget.
+var widgetScript = template.Must(template.New("widget.js").Parse(`/* goverter widget v{{.Version}} */
+(function () {
+  var script = document.currentScript;
+  var origin = new URL(script.src).origin;
+  var frames = [];
+
+  function mount(el) {
+    if (el.getAttribute("data-goverter-mounted")) return;
+    el.setAttribute("data-goverter-mounted", "1");
+    var params = new URLSearchParams({ v: "{{.Version}}" });
+    var dims = el.getAttribute("data-dimensions");
+    if (dims) params.set("dimensions", dims);
+    var frame = document.createElement("iframe");
+    frame.src = origin + "{{.BasePath}}/widget?" + params.toString();
+    frame.title = "Unit converter";
+    frame.loading = "lazy";
+    frame.style.cssText = "width:100%;max-width:420px;height:220px;border:0;";
+    el.appendChild(frame);
+    frames.push(frame);
+  }
+
+  // The widget reports its height so the iframe never shows scrollbars
+  window.addEventListener("message", function (event) {
+    if (event.origin !== origin || !event.data || event.data.goverterHeight === undefined) return;
+    frames.forEach(function (frame) {
+      if (frame.contentWindow === event.source) frame.style.height = event.data.goverterHeight + "px";
+    });
+  });
+
+  function mountAll() {
+    document.querySelectorAll("[data-goverter-widget]").forEach(mount);
+  }
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", mountAll);
+  } else {
+    mountAll();
+  }
+})();
+`))
+
+// WidgetData represents the data passed to the widget template
+type WidgetData struct {
+	Version        string
+	Units          map[string]map[string]converter.Unit
+	Dimensions     []string
+	DimensionNames map[string]string
+}
+
+// Handler for the widget loader script
+func widgetScriptHandler(w http.ResponseWriter, r *http.Request) {
+	etag := `"widget-` + widgetVersion + `"`
+	w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if err := widgetScript.Execute(w, map[string]string{"Version": widgetVersion, "BasePath": BasePath(r.Context())}); err != nil {
+		log.Printf("Error rendering widget script: %v", err)
+	}
+}
+
+// Handler for the embeddable widget page
+func widgetHandler(uc *converter.UnitConverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Restrict the widget to the requested dimensions, ignoring unknown ones
+		available := uc.GetAllDimensions()
+		dimensions := available
+		if requested := r.URL.Query().Get("dimensions"); requested != "" {
+			known := make(map[string]bool, len(available))
+			for _, dim := range available {
+				known[dim] = true
+			}
+			var chosen []string
+			for _, dim := range strings.Split(requested, ",") {
+				if dim = strings.TrimSpace(dim); known[dim] {
+					chosen = append(chosen, dim)
+				}
+			}
+			if len(chosen) > 0 {
+				sort.Strings(chosen)
+				dimensions = chosen
+			}
+		}
+
+		data := WidgetData{
+			Version:        widgetVersion,
+			Units:          make(map[string]map[string]converter.Unit, len(dimensions)),
+			Dimensions:     dimensions,
+			DimensionNames: make(map[string]string, len(dimensions)),
+		}
+		for _, dim := range dimensions {
+			data.Units[dim] = uc.GetUnitsByDimension(dim)
+			data.DimensionNames[dim] = uc.GetDimensionName(dim)
+		}
+
+		tmpl, err := htmltemplate.New("widget.html").Funcs(pageFuncs(r)).ParseFiles("templates/widget.html")
+		if err != nil {
+			http.Error(w, "Error loading template: "+err.Error(), http.StatusInternalServerError)
+			log.Printf("Error loading template: %v", err)
+			return
+		}
+
+		// Any site may frame the widget
+		w.Header().Set("Content-Security-Policy", "frame-ancestors *")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := tmpl.Execute(w, data); err != nil {
+			log.Printf("Error rendering template: %v", err)
+		}
+	}
+}
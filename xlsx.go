@@ -0,0 +1,199 @@
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/monsieurr/goverter/converter"
+)
+
+// A minimal XLSX (Office Open XML) writer for numeric tables: one sheet,
+// a bold header row that stays in view, and a number format per column.
+// Strings are written inline, so no shared string table is needed.
+
+// xlsxColumn describes one column of a spreadsheet.
+type xlsxColumn struct {
+	Header string
+	Format string // Excel number format code, e.g. "#,##0.000"; empty for General
+}
+
+// xlsxNumberFormat returns the number format for results in unit: the
+// configured precision for its dimension, or three decimals as on /convert.
+func xlsxNumberFormat(uc *converter.UnitConverter, unit string) string {
+	places, ok := uc.Precision(unit)
+	if !ok {
+		places = 3
+	}
+	if places == 0 {
+		return "#,##0"
+	}
+	return "#,##0." + strings.Repeat("0", places)
+}
+
+// xlsxUnitColumn returns the column for values in unit, headed by the
+// unit's name and symbol and formatted to its precision.
+func xlsxUnitColumn(uc *converter.UnitConverter, unit string) xlsxColumn {
+	ref := uc.Resolve(unit)
+	header := ref.Symbol()
+	if name := ref.Unit().Name; name != "" {
+		header = name + " (" + header + ")"
+	}
+	return xlsxColumn{Header: header, Format: xlsxNumberFormat(uc, unit)}
+}
+
+// xlsxColumnName returns the letters of a zero-based column index: A, B, ... Z, AA, AB...
+func xlsxColumnName(index int) string {
+	name := ""
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = string(rune('A'+(index-1)%26)) + name
+	}
+	return name
+}
+
+// xmlEscape escapes text for XML content and attributes.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '&':
+			b.WriteString("&amp;")
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// writeXLSX writes a workbook with a single sheet of numbers. NaN and
+// infinite values, which spreadsheets can't store, are left empty.
+func writeXLSX(w io.Writer, sheet string, columns []xlsxColumn, rows [][]float64) error {
+	// Style 0 is the default and style 1 the bold header; each distinct
+	// number format gets a style of its own
+	formatIDs := make(map[string]int)
+	var formats []string
+	columnStyles := make([]int, len(columns))
+	for i, column := range columns {
+		if column.Format == "" {
+			continue
+		}
+		if _, ok := formatIDs[column.Format]; !ok {
+			formatIDs[column.Format] = len(formats)
+			formats = append(formats, column.Format)
+		}
+		columnStyles[i] = 2 + formatIDs[column.Format]
+	}
+
+	var styles strings.Builder
+	styles.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	styles.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(formats) > 0 {
+		fmt.Fprintf(&styles, `<numFmts count="%d">`, len(formats))
+		for i, format := range formats {
+			// Custom formats start at 164
+			fmt.Fprintf(&styles, `<numFmt numFmtId="%d" formatCode="%s"/>`, 164+i, xmlEscape(format))
+		}
+		styles.WriteString(`</numFmts>`)
+	}
+	styles.WriteString(`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>`)
+	styles.WriteString(`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>`)
+	styles.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	styles.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&styles, `<cellXfs count="%d">`, 2+len(formats))
+	styles.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+	styles.WriteString(`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>`)
+	for i := range formats {
+		fmt.Fprintf(&styles, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, 164+i)
+	}
+	styles.WriteString(`</cellXfs></styleSheet>`)
+
+	var data strings.Builder
+	data.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	data.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	// Keep the header row in view while scrolling
+	data.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	data.WriteString(`<cols>`)
+	for i, column := range columns {
+		width := max(12, len([]rune(column.Header))+2)
+		fmt.Fprintf(&data, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+	}
+	data.WriteString(`</cols><sheetData><row r="1">`)
+	for i, column := range columns {
+		fmt.Fprintf(&data, `<c r="%s1" t="inlineStr" s="1"><is><t>%s</t></is></c>`, xlsxColumnName(i), xmlEscape(column.Header))
+	}
+	data.WriteString(`</row>`)
+	for r, row := range rows {
+		fmt.Fprintf(&data, `<row r="%d">`, r+2)
+		for i, value := range row {
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			fmt.Fprintf(&data, `<c r="%s%d" s="%d"><v>%s</v></c>`, xlsxColumnName(i), r+2, columnStyles[i], strconv.FormatFloat(value, 'g', -1, 64))
+		}
+		data.WriteString(`</row>`)
+	}
+	data.WriteString(`</sheetData>`)
+	if len(rows) > 0 {
+		fmt.Fprintf(&data, `<autoFilter ref="A1:%s%d"/>`, xlsxColumnName(len(columns)-1), len(rows)+1)
+	}
+	data.WriteString(`</worksheet>`)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="` + xmlEscape(sheet) + `" sheetId="1" r:id="rId1"/></sheets>` +
+			`<definedNames><definedName name="_xlnm._FilterDatabase" localSheetId="0" hidden="1">'` + xmlEscape(sheet) + `'!$A$1:$` + xlsxColumnName(len(columns)-1) + `$` + strconv.Itoa(len(rows)+1) + `</definedName></definedNames>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`},
+		{"xl/styles.xml", styles.String()},
+		{"xl/worksheets/sheet1.xml", data.String()},
+	}
+
+	archive := zip.NewWriter(w)
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// serveXLSX sends a workbook as a download named filename.
+func serveXLSX(w http.ResponseWriter, filename, sheet string, columns []xlsxColumn, rows [][]float64) {
+	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if err := writeXLSX(w, sheet, columns, rows); err != nil {
+		log.Printf("Error writing %s: %v", filename, err)
+	}
+}
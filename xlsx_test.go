@@ -0,0 +1,198 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestXLSXColumnName(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{27, "AB"},
+		{51, "AZ"},
+		{52, "BA"},
+		{701, "ZZ"},
+		{702, "AAA"},
+	}
+	for _, tt := range tests {
+		if got := xlsxColumnName(tt.index); got != tt.want {
+			t.Errorf("xlsxColumnName(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+}
+
+// readXLSX unpacks a workbook written by writeXLSX, checking that every
+// part is well-formed XML.
+func readXLSX(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("not a zip archive: %v", err)
+	}
+	parts := make(map[string][]byte)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+		parts[f.Name] = content
+	}
+	return parts
+}
+
+// xlsxSheet is the part of a worksheet the tests look at.
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Style  int    `xml:"s,attr"`
+			Value  string `xml:"v"`
+			Inline string `xml:"is>t"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+	AutoFilter struct {
+		Ref string `xml:"ref,attr"`
+	} `xml:"autoFilter"`
+}
+
+func TestWriteXLSX(t *testing.T) {
+	columns := []xlsxColumn{
+		{Header: "Metre (m)", Format: "#,##0.000"},
+		{Header: "Feet & inches <ft>", Format: "#,##0.000"},
+		{Header: "Count"},
+	}
+	rows := [][]float64{
+		{1, 3.280839895013123, 7},
+		{2, math.NaN(), math.Inf(1)},
+	}
+	var buf bytes.Buffer
+	if err := writeXLSX(&buf, "Length", columns, rows); err != nil {
+		t.Fatalf("writeXLSX: %v", err)
+	}
+	parts := readXLSX(t, buf.Bytes())
+	for _, name := range []string{
+		"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml",
+	} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(parts["xl/workbook.xml"], &workbook); err != nil {
+		t.Fatal(err)
+	}
+	if len(workbook.Sheets) != 1 || workbook.Sheets[0].Name != "Length" {
+		t.Errorf("sheets = %+v, want one named Length", workbook.Sheets)
+	}
+
+	// Both columns share one custom format, so one style after the header's
+	var styles struct {
+		Formats []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		CellXfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+			FontID   int `xml:"fontId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := xml.Unmarshal(parts["xl/styles.xml"], &styles); err != nil {
+		t.Fatal(err)
+	}
+	if len(styles.Formats) != 1 || styles.Formats[0].ID != 164 || styles.Formats[0].Code != "#,##0.000" {
+		t.Errorf("number formats = %+v", styles.Formats)
+	}
+	if len(styles.CellXfs) != 3 || styles.CellXfs[1].FontID != 1 || styles.CellXfs[2].NumFmtID != 164 {
+		t.Errorf("cell styles = %+v", styles.CellXfs)
+	}
+
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 3 {
+		t.Fatalf("got %d rows, want a header and 2 rows", len(sheet.Rows))
+	}
+	var headers []string
+	for _, c := range sheet.Rows[0].Cells {
+		if c.Type != "inlineStr" || c.Style != 1 {
+			t.Errorf("header cell %s: type %q, style %d", c.R, c.Type, c.Style)
+		}
+		headers = append(headers, c.Inline)
+	}
+	if want := []string{"Metre (m)", "Feet & inches <ft>", "Count"}; !reflect.DeepEqual(headers, want) {
+		t.Errorf("headers = %q, want %q", headers, want)
+	}
+
+	type cell struct {
+		ref, value string
+		style      int
+	}
+	var cells []cell
+	for _, row := range sheet.Rows[1:] {
+		for _, c := range row.Cells {
+			cells = append(cells, cell{c.R, c.Value, c.Style})
+		}
+	}
+	want := []cell{
+		{"A2", "1", 2},
+		{"B2", "3.280839895013123", 2},
+		{"C2", "7", 0},
+		{"A3", "2", 2}, // NaN and infinity are left empty
+	}
+	if !reflect.DeepEqual(cells, want) {
+		t.Errorf("cells = %+v, want %+v", cells, want)
+	}
+	if sheet.AutoFilter.Ref != "A1:C3" {
+		t.Errorf("autoFilter ref = %q, want A1:C3", sheet.AutoFilter.Ref)
+	}
+}
+
+func TestWriteXLSXEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeXLSX(&buf, "Empty", []xlsxColumn{{Header: "Value"}}, nil); err != nil {
+		t.Fatalf("writeXLSX: %v", err)
+	}
+	parts := readXLSX(t, buf.Bytes())
+	if bytes.Contains(parts["xl/styles.xml"], []byte("numFmts")) {
+		t.Error("styles list number formats though no column has one")
+	}
+	var sheet xlsxSheet
+	if err := xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet); err != nil {
+		t.Fatal(err)
+	}
+	if len(sheet.Rows) != 1 || sheet.AutoFilter.Ref != "" {
+		t.Errorf("got %d rows and autoFilter %q, want the header alone", len(sheet.Rows), sheet.AutoFilter.Ref)
+	}
+}